@@ -1,7 +1,11 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/elaurentium/exilium-blog-backend/internal/domain/services"
@@ -9,6 +13,7 @@ import (
 	"github.com/elaurentium/exilium-blog-backend/internal/infra/api/handlers"
 	"github.com/elaurentium/exilium-blog-backend/internal/infra/api/middleware"
 	"github.com/elaurentium/exilium-blog-backend/internal/infra/auth"
+	"github.com/elaurentium/exilium-blog-backend/internal/infra/mailer"
 	"github.com/elaurentium/exilium-blog-backend/internal/infra/persistence/db"
 	"github.com/elaurentium/exilium-blog-backend/internal/infra/persistence/redis"
 	"github.com/elaurentium/exilium-blog-backend/pkg/logger"
@@ -38,17 +43,116 @@ func main() {
 
 	// Inicializa os repositórios e serviços
 	userRepo := db.NewUserRepository(pool)
-	authService := auth.NewAuthService()
-	userService := services.NewUserService(userRepo, authService)
-	userHandler := handlers.NewUserHandler(userService)
+	refreshTokenRepo := db.NewRefreshTokenRepository(pool)
+	sessionRepo := db.NewSessionRepository(pool)
+	usernameHistoryRepo := db.NewUsernameHistoryRepository(pool)
+	// Novas senhas usam argon2id; hashes bcrypt antigos são migrados no login
+	passwordHasher := auth.NewMultiHasher(auth.NewPasswordService(), auth.NewBcryptHasher())
+	jwtConfig, err := auth.LoadJWTConfig()
+	if err != nil {
+		logger.Info("Invalid JWT configuration: %v", err)
+		return
+	}
+	jwtService := auth.NewJWTService(jwtConfig)
+	denylist := redis.NewTokenDenylist(redisClient)
+
+	mail, err := mailer.NewMailerFromEnv()
+	if err != nil {
+		logger.Info("Failed to configure mailer: %v", err)
+		return
+	}
+	verificationService := services.NewEmailVerificationService(userRepo, jwtService, mail, redis.NewCooldownStore(redisClient), os.Getenv("APP_URL")+"/verify-email")
+	recoveryCodeRepo := db.NewRecoveryCodeRepository(pool)
+	twoFactorService := services.NewTwoFactorService(userRepo, recoveryCodeRepo, passwordHasher, jwtConfig.Issuer)
+	twoFactorHandler := handlers.NewTwoFactorHandler(twoFactorService)
+	// Bloqueio progressivo por conta e por IP, compartilhado entre instâncias via Redis
+	loginThrottle := auth.NewDefaultLoginThrottle(redis.NewAttemptStore(redisClient))
+	authEventRepo := db.NewAuthEventRepository(pool)
+	loginGuard := services.NewLoginGuard(loginThrottle, authEventRepo)
+	userService := services.NewUserService(userRepo, refreshTokenRepo, sessionRepo, usernameHistoryRepo, passwordHasher, jwtService, denylist, verificationService, twoFactorService, loginGuard)
+	userHandler := handlers.NewUserHandler(userService, verificationService)
+
+	// Login social: provedores configurados via OIDC_PROVIDERS
+	oidcConfigs, err := auth.LoadOIDCProviders()
+	if err != nil {
+		logger.Info("Invalid OIDC configuration: %v", err)
+		return
+	}
+	var oidcProviders []*auth.OIDCProvider
+	for _, config := range oidcConfigs {
+		oidcProviders = append(oidcProviders, auth.NewOIDCProvider(config))
+	}
+	userIdentityRepo := db.NewUserIdentityRepository(pool)
+	oidcService := services.NewOIDCService(oidcProviders, userIdentityRepo, userRepo, usernameHistoryRepo, userService, jwtService)
+	oidcHandler := handlers.NewOIDCHandler(oidcService, strings.HasPrefix(os.Getenv("APP_URL"), "https://"))
+
+	passwordResetRepo := db.NewPasswordResetRepository(pool)
+	passwordResetService := services.NewPasswordResetService(userService, userRepo, passwordResetRepo, mail, os.Getenv("APP_URL")+"/reset-password")
+	passwordHandler := handlers.NewPasswordHandler(passwordResetService)
+
+	postRepo := db.NewPostRepository(pool)
+	commentRepo := db.NewCommentRepository(pool)
+	subRepo := db.NewSubRepository(pool)
+	blockRepo := db.NewBlockRepository(pool)
+	voteRepo := db.NewVoteRepository(pool)
+
+	// Com REQUIRE_VERIFIED_EMAIL=true só quem confirmou o e-mail pode publicar
+	postService := services.NewPostService(postRepo, userRepo, subRepo, voteRepo, os.Getenv("REQUIRE_VERIFIED_EMAIL") == "true")
+	commentService := services.NewCommentService(commentRepo, postRepo, subRepo, userRepo, blockRepo, voteRepo)
+	subService := services.NewSubService(subRepo, userRepo)
+
+	postHandler := handlers.NewPostHandler(postService)
+	commentHandler := handlers.NewCommentHandler(commentService)
+	subHandler := handlers.NewSubHandler(subService)
+	followRepo := db.NewFollowRepository(pool)
+	profileService := services.NewProfileService(userRepo, postRepo, commentRepo, followRepo, usernameHistoryRepo)
+	profileHandler := handlers.NewProfileHandler(profileService)
+	accountRepo := db.NewAccountRepository(pool)
+	accountService := services.NewAccountService(accountRepo, userRepo, postRepo, commentRepo, passwordHasher, userService)
+	accountHandler := handlers.NewAccountHandler(accountService)
+	followService := services.NewFollowService(followRepo, userRepo, postRepo, blockRepo)
+	followHandler := handlers.NewFollowHandler(followService)
+	blockService := services.NewBlockService(blockRepo, followRepo, userRepo)
+	blockHandler := handlers.NewBlockHandler(blockService)
+
+	// Contas apagadas são removidas de vez após ACCOUNT_PURGE_GRACE_PERIOD (padrão: 30 dias).
+	// Um valor inválido derruba a inicialização: cair no padrão poderia apagar
+	// contas antes do prazo que o operador configurou.
+	purgeGrace := 30 * 24 * time.Hour
+	if value := os.Getenv("ACCOUNT_PURGE_GRACE_PERIOD"); value != "" {
+		purgeGrace, err = time.ParseDuration(value)
+		if err == nil && purgeGrace <= 0 {
+			err = fmt.Errorf("must be positive")
+		}
+		if err != nil {
+			logger.Error(fmt.Sprintf("Invalid ACCOUNT_PURGE_GRACE_PERIOD %q: %v", value, err))
+			redisClient.Close()
+			pool.Close()
+			os.Exit(1)
+		}
+	}
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for ; true; <-ticker.C {
+			if _, err := accountService.PurgeDeleted(context.Background(), purgeGrace); err != nil {
+				logger.Info("Failed to purge deleted accounts: %v", err)
+			}
+		}
+	}()
+
+	jwksHandler := handlers.NewJWKSHandler(jwtService)
+	auditLogRepo := db.NewAuditLogRepository(pool)
+	impersonationService := services.NewImpersonationService(userRepo, auditLogRepo, jwtService)
+	adminHandler := handlers.NewAdminHandler(loginGuard, impersonationService)
 
-	postHandler := &handlers.PostHandler{}
-	commentHandler := &handlers.CommentHandler{}
-	subHandler := &handlers.SubHandler{}
-	authMiddleware := &middleware.AuthMiddleware{}
+	apiTokenRepo := db.NewAPITokenRepository(pool)
+	apiTokenService := services.NewAPITokenService(apiTokenRepo, userRepo)
+	apiTokenHandler := handlers.NewAPITokenHandler(apiTokenService)
+	authMiddleware := middleware.NewAuthMiddleware(jwtService, denylist, apiTokenService, impersonationService)
 
 	// Cria o roteador
-	router := api.NewRouter(userHandler, postHandler, commentHandler, subHandler, authMiddleware, redisClient)
+	router := api.NewRouter(userHandler, postHandler, commentHandler, subHandler, passwordHandler, jwksHandler, twoFactorHandler, adminHandler, apiTokenHandler, oidcHandler, profileHandler, accountHandler, followHandler, blockHandler, authMiddleware, redisClient)
 
 	// Inicia o servidor HTTP
 	server := &http.Server{
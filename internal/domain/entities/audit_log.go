@@ -0,0 +1,22 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AuditImpersonationStart   = "impersonation.start"
+	AuditImpersonationRequest = "impersonation.request"
+)
+
+type AuditLog struct {
+	ID           uuid.UUID         `json:"id"`
+	ActorID      *uuid.UUID        `json:"actor_id,omitempty"`
+	TargetUserID *uuid.UUID        `json:"target_user_id,omitempty"`
+	Action       string            `json:"action"`
+	Details      map[string]string `json:"details"`
+	IP           string            `json:"ip"`
+	CreatedAt    time.Time         `json:"created_at"`
+}
@@ -0,0 +1,24 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AuthEventLoginSucceeded = "login_succeeded"
+	AuthEventLoginFailed    = "login_failed"
+	AuthEventLoginLocked    = "login_locked"
+	AuthEventMFAFailed      = "mfa_failed"
+)
+
+type AuthEvent struct {
+	ID        uuid.UUID  `json:"id"`
+	UserID    *uuid.UUID `json:"user_id,omitempty"`
+	Email     string     `json:"email"`
+	IP        string     `json:"ip"`
+	UserAgent string     `json:"user_agent"`
+	Type      string     `json:"type"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -0,0 +1,21 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Bloquear esconde o conteúdo do outro usuário e o impede de responder aos
+// posts e comentários de quem bloqueou; silenciar apenas esconde.
+const (
+	BlockKindBlock = "block"
+	BlockKindMute  = "mute"
+)
+
+type Block struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Username  string    `json:"username"`
+	Kind      string    `json:"kind"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -7,14 +7,16 @@ import (
 )
 
 type Comment struct {
-	ID        uuid.UUID  `json:"id"`
-	Content   string     `json:"content"`
-	UserID    uuid.UUID  `json:"user_id"`
-	PostID    uuid.UUID  `json:"post_id"`
-	ParentID  *uuid.UUID `json:"parent_id,omitempty"`
-	Upvotes   int        `json:"upvotes"`
-	Downvotes int        `json:"downvotes"`
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
-	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	ID         uuid.UUID  `json:"id"`
+	Content    string     `json:"content"`
+	UserID     uuid.UUID  `json:"user_id"`
+	Author     string     `json:"author"`
+	PostID     uuid.UUID  `json:"post_id"`
+	ParentID   *uuid.UUID `json:"parent_id,omitempty"`
+	Upvotes    int        `json:"upvotes"`
+	Downvotes  int        `json:"downvotes"`
+	ViewerVote *int       `json:"viewer_vote,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty"`
 }
@@ -0,0 +1,17 @@
+package entities
+
+// Ordenações dos comentários (?sort=). Em Q&A, os comentários do autor do
+// post e os que ele respondeu vêm primeiro.
+const (
+	CommentSortBest          = "best"
+	CommentSortTop           = "top"
+	CommentSortNew           = "new"
+	CommentSortOld           = "old"
+	CommentSortControversial = "controversial"
+	CommentSortQA            = "qa"
+)
+
+// CommentSorts lista as ordenações aceitas, inclusive como sugestão do post.
+var CommentSorts = []string{
+	CommentSortBest, CommentSortTop, CommentSortNew, CommentSortOld, CommentSortControversial, CommentSortQA,
+}
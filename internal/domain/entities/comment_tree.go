@@ -0,0 +1,42 @@
+package entities
+
+import "github.com/google/uuid"
+
+// CommentTreeQuery descreve um trecho da árvore de comentários de um post:
+// os filhos de ParentID (nil para os comentários de primeiro nível), a
+// partir de Offset, e seus descendentes até Depth níveis. Limit vale para o
+// primeiro nível e Children para os demais.
+type CommentTreeQuery struct {
+	PostID   uuid.UUID
+	ParentID *uuid.UUID
+	ViewerID uuid.UUID
+	Sort     string
+	Depth    int
+	Limit    int
+	Children int
+	Offset   int
+}
+
+type CommentNode struct {
+	*Comment
+	ReplyCount int            `json:"reply_count"`
+	Replies    []*CommentNode `json:"replies"`
+	More       *CommentMore   `json:"more,omitempty"`
+
+	// Preenchidos pelo repositório para montar a árvore
+	Depth        int `json:"-"`
+	Position     int `json:"-"`
+	SiblingCount int `json:"-"`
+}
+
+// CommentMore representa respostas que ficaram de fora; Continuation é o
+// token para buscá-las em GET /posts/:id/comments/tree?continue=.
+type CommentMore struct {
+	Count        int    `json:"count"`
+	Continuation string `json:"continuation"`
+}
+
+type CommentTree struct {
+	Comments []*CommentNode `json:"comments"`
+	More     *CommentMore   `json:"more,omitempty"`
+}
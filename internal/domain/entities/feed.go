@@ -0,0 +1,19 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PostCursor marca o último post de uma página: a próxima começa logo depois
+// dele na ordem (created_at, id) decrescente.
+type PostCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+type FeedPage struct {
+	Posts      []*Post `json:"posts"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
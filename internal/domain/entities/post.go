@@ -11,11 +11,17 @@ type Post struct {
 	Title       string     `json:"title"`
 	Content     string     `json:"content"`
 	UserID      uuid.UUID  `json:"user_id"`
+	Author      string     `json:"author"`
 	SubID 		uuid.UUID  `json:"sub_id"`
 	Upvotes     int        `json:"upvotes"`
 	Downvotes   int        `json:"downvotes"`
 	IsLocked    bool       `json:"is_locked"`
 	IsPinned    bool       `json:"is_pinned"`
+	// SuggestedSort é a ordenação padrão dos comentários deste post
+	SuggestedSort *string  `json:"suggested_sort,omitempty"`
+	// ViewerVote é o voto de quem fez a requisição (1, 0 ou -1); fica de fora
+	// para visitantes anônimos
+	ViewerVote  *int       `json:"viewer_vote,omitempty"`
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
 	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
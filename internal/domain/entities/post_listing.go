@@ -0,0 +1,27 @@
+package entities
+
+// Ordenações das listagens de posts (?sort=)
+const (
+	PostSortHot           = "hot"
+	PostSortNew           = "new"
+	PostSortTop           = "top"
+	PostSortRising        = "rising"
+	PostSortControversial = "controversial"
+)
+
+// Janelas de tempo de top e controversial (?t=)
+const (
+	TimeWindowHour  = "hour"
+	TimeWindowDay   = "day"
+	TimeWindowWeek  = "week"
+	TimeWindowMonth = "month"
+	TimeWindowYear  = "year"
+	TimeWindowAll   = "all"
+)
+
+// PostListing é uma ordenação já validada. Window só vale para top e
+// controversial.
+type PostListing struct {
+	Sort   string
+	Window string
+}
@@ -0,0 +1,67 @@
+package entities
+
+// Papéis do site em ordem crescente: cada papel herda as permissões dos
+// papéis abaixo dele.
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+var roleRank = map[string]int{
+	RoleUser:      1,
+	RoleModerator: 2,
+	RoleAdmin:     3,
+}
+
+const (
+	PermissionPostDeleteAny    = "post.delete.any"
+	PermissionCommentDeleteAny = "comment.delete.any"
+	PermissionSubBan           = "sub.ban"
+	PermissionSubUpdateAny     = "sub.update.any"
+	PermissionSubDeleteAny     = "sub.delete.any"
+	PermissionAuthEventsRead   = "auth_events.read"
+	PermissionAuditLogRead     = "audit_log.read"
+	PermissionUserImpersonate  = "user.impersonate"
+)
+
+// rolePermissions lista apenas o que cada papel acrescenta ao anterior.
+var rolePermissions = map[string][]string{
+	RoleUser: {},
+	RoleModerator: {
+		PermissionPostDeleteAny,
+		PermissionCommentDeleteAny,
+		PermissionSubBan,
+	},
+	RoleAdmin: {
+		PermissionSubUpdateAny,
+		PermissionSubDeleteAny,
+		PermissionAuthEventsRead,
+		PermissionAuditLogRead,
+		PermissionUserImpersonate,
+	},
+}
+
+// RoleAtLeast informa se role está no mesmo nível ou acima de min. Papéis
+// desconhecidos não passam em nenhuma verificação.
+func RoleAtLeast(role, min string) bool {
+	rank, ok := roleRank[role]
+	if !ok {
+		return false
+	}
+	return rank >= roleRank[min]
+}
+
+func HasPermission(role, permission string) bool {
+	for r, permissions := range rolePermissions {
+		if !RoleAtLeast(role, r) {
+			continue
+		}
+		for _, p := range permissions {
+			if p == permission {
+				return true
+			}
+		}
+	}
+	return false
+}
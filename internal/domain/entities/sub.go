@@ -15,7 +15,17 @@ type Sub struct {
 	IsPrivate   bool       `json:"is_private"`
 	BannerURL   string     `json:"banner_url"`
 	IconURL     string     `json:"icon_url"`
+	MinKarma    int        `json:"min_karma"`
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
 	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
-}
\ No newline at end of file
+}
+
+// SubBan impede o usuário de postar e comentar no sub.
+type SubBan struct {
+	SubID     uuid.UUID `json:"sub_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	BannedBy  uuid.UUID `json:"banned_by"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
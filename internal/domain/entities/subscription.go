@@ -0,0 +1,15 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Subscription struct {
+	ID                     uuid.UUID `json:"id"`
+	UserID                 uuid.UUID `json:"user_id"`
+	SubID                  uuid.UUID `json:"sub_id"`
+	NotificationPreference string    `json:"notification_preference"`
+	SubscribedAt           time.Time `json:"subscribed_at"`
+}
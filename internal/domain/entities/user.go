@@ -19,8 +19,49 @@ type User struct {
 	Role           string     `json:"role"`
 	IsActive       bool       `json:"is_active"`
 	EmailVerified  bool       `json:"email_verified"`
+	TOTPSecret     string     `json:"-"`
+	TOTPEnabled    bool       `json:"totp_enabled"`
+	PostKarma      int        `json:"post_karma"`
+	CommentKarma   int        `json:"comment_karma"`
 	LastLogin      *time.Time `json:"last_login"`
 	CreatedAt      time.Time  `json:"created_at"`
 	UpdatedAt      time.Time  `json:"updated_at"`
 	DeletedAt      *time.Time `json:"deleted_at,omitempty"`
+}
+
+// Karma é a reputação total: votos recebidos em posts e comentários.
+func (u *User) Karma() int {
+	return u.PostKarma + u.CommentKarma
+}
+
+// PublicUser é o que qualquer visitante pode ver de um perfil: nada de
+// e-mail, data de nascimento ou dados de login.
+type PublicUser struct {
+	ID           uuid.UUID `json:"id"`
+	Username     string    `json:"username"`
+	FullName     string    `json:"full_name"`
+	Bio          string    `json:"bio"`
+	AvatarURL    string    `json:"avatar_url"`
+	Role         string    `json:"role"`
+	Karma        int       `json:"karma"`
+	PostKarma    int       `json:"post_karma"`
+	CommentKarma int       `json:"comment_karma"`
+	Followers    int       `json:"followers"`
+	Following    int       `json:"following"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (u *User) Public() *PublicUser {
+	return &PublicUser{
+		ID:           u.ID,
+		Username:     u.Username,
+		FullName:     u.FullName,
+		Bio:          u.Bio,
+		AvatarURL:    u.AvatarURL,
+		Role:         u.Role,
+		Karma:        u.Karma(),
+		PostKarma:    u.PostKarma,
+		CommentKarma: u.CommentKarma,
+		CreatedAt:    u.CreatedAt,
+	}
 }
\ No newline at end of file
@@ -0,0 +1,14 @@
+package entities
+
+import "time"
+
+// UserDataExport reúne tudo o que o usuário produziu, para o export de
+// dados pessoais (LGPD/GDPR).
+type UserDataExport struct {
+	GeneratedAt   time.Time       `json:"generated_at"`
+	Profile       *User           `json:"profile"`
+	Posts         []*Post         `json:"posts"`
+	Comments      []*Comment      `json:"comments"`
+	Votes         []*Vote         `json:"votes"`
+	Subscriptions []*Subscription `json:"subscriptions"`
+}
@@ -0,0 +1,35 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tipos gravados na coluna votes.type (enum vote_type)
+const (
+	VoteTypeUp   = "upvote"
+	VoteTypeDown = "downvote"
+)
+
+type Vote struct {
+	ID        uuid.UUID  `json:"id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	PostID    *uuid.UUID `json:"post_id,omitempty"`
+	CommentID *uuid.UUID `json:"comment_id,omitempty"`
+	Type      string     `json:"type"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// VoteDirection converte o tipo do voto na direção usada pela API: 1 para
+// upvote, -1 para downvote e 0 sem voto.
+func VoteDirection(voteType string) int {
+	switch voteType {
+	case VoteTypeUp:
+		return 1
+	case VoteTypeDown:
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,21 @@
+package entities
+
+import "testing"
+
+func TestVoteDirection(t *testing.T) {
+	tests := []struct {
+		voteType string
+		expected int
+	}{
+		{voteType: VoteTypeUp, expected: 1},
+		{voteType: VoteTypeDown, expected: -1},
+		{voteType: "", expected: 0},
+		{voteType: "sideways", expected: 0},
+	}
+
+	for _, tc := range tests {
+		if got := VoteDirection(tc.voteType); got != tc.expected {
+			t.Errorf("VoteDirection(%q) = %d, want %d", tc.voteType, got, tc.expected)
+		}
+	}
+}
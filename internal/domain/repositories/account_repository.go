@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/google/uuid"
+)
+
+// AccountRepository cuida do ciclo de vida da conta: exclusão lógica, purge
+// definitivo e os dados que entram no export.
+type AccountRepository interface {
+	SoftDelete(ctx context.Context, userID uuid.UUID) error
+	ListDeletedBefore(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error)
+	Purge(ctx context.Context, userID uuid.UUID) error
+	ListVotes(ctx context.Context, userID uuid.UUID) ([]*entities.Vote, error)
+	ListSubscriptions(ctx context.Context, userID uuid.UUID) ([]*entities.Subscription, error)
+}
@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/google/uuid"
+)
+
+type APITokenRepository interface {
+	// Create só grava o token se o usuário tiver menos de limit tokens
+	// ativos (nem revogados nem expirados); retorna false quando o limite
+	// já foi atingido.
+	Create(ctx context.Context, token *entities.APIToken, limit int) (bool, error)
+	GetByHash(ctx context.Context, tokenHash string) (*entities.APIToken, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*entities.APIToken, error)
+	// Revoke retorna false se o token não existe, não pertence ao usuário ou
+	// já estava revogado.
+	Revoke(ctx context.Context, id, userID uuid.UUID) (bool, error)
+	TouchLastUsed(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/google/uuid"
+)
+
+type AuditLogRepository interface {
+	Create(ctx context.Context, log *entities.AuditLog) error
+	// List filtra por ator ou alvo quando userID é informado.
+	List(ctx context.Context, userID *uuid.UUID, limit, offset int) ([]*entities.AuditLog, error)
+}
@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/google/uuid"
+)
+
+type AuthEventRepository interface {
+	Create(ctx context.Context, event *entities.AuthEvent) error
+	// List retorna os eventos mais recentes primeiro; userID nil lista todos.
+	List(ctx context.Context, userID *uuid.UUID, limit, offset int) ([]*entities.AuthEvent, error)
+}
@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/google/uuid"
+)
+
+type BlockRepository interface {
+	Create(ctx context.Context, blockerID, blockedID uuid.UUID, kind string) error
+	Delete(ctx context.Context, blockerID, blockedID uuid.UUID, kind string) error
+	ListByUser(ctx context.Context, blockerID uuid.UUID) ([]*entities.Block, error)
+	IsBlocked(ctx context.Context, blockerID, blockedID uuid.UUID) (bool, error)
+}
@@ -9,13 +9,18 @@ import (
 
 type CommentRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.Comment, error)
-	GetByPost(ctx context.Context, postID uuid.UUID, limit, offset int) ([]*entities.Comment, error)
+	GetByPost(ctx context.Context, postID, viewerID uuid.UUID, sort string, limit, offset int) ([]*entities.Comment, error)
 	GetByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.Comment, error)
-	GetReplies(ctx context.Context, parentID uuid.UUID, limit, offset int) ([]*entities.Comment, error)
+	GetReplies(ctx context.Context, parentID, viewerID uuid.UUID, sort string, limit, offset int) ([]*entities.Comment, error)
+	GetTree(ctx context.Context, query entities.CommentTreeQuery) ([]*entities.CommentNode, error)
+	// GetTreeComment é o GetByID da árvore: comentários apagados com
+	// respostas vivas voltam como "[deleted]"
+	GetTreeComment(ctx context.Context, id uuid.UUID) (*entities.Comment, error)
+	GetAncestors(ctx context.Context, id uuid.UUID, limit int) ([]*entities.CommentNode, error)
+	// IsVisibleTo diz se nem o comentário nem seus ancestrais são de alguém
+	// que viewerID bloqueou ou silenciou
+	IsVisibleTo(ctx context.Context, id, viewerID uuid.UUID) (bool, error)
 	Create(ctx context.Context, comment *entities.Comment) error
 	Update(ctx context.Context, comment *entities.Comment) error
 	Delete(ctx context.Context, id uuid.UUID) error
-	UpvoteComment(ctx context.Context, commentID, userID uuid.UUID) error
-	DownvoteComment(ctx context.Context, commentID, userID uuid.UUID) error
-	RemoveVote(ctx context.Context, commentID, userID uuid.UUID) error
 }
\ No newline at end of file
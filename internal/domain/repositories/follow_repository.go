@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type FollowRepository interface {
+	Follow(ctx context.Context, followerID, followeeID uuid.UUID) error
+	Unfollow(ctx context.Context, followerID, followeeID uuid.UUID) error
+	// Counts devolve quantos seguem o usuário e quantos ele segue
+	Counts(ctx context.Context, userID uuid.UUID) (followers int, following int, err error)
+}
@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/google/uuid"
+)
+
+type PasswordResetRepository interface {
+	Create(ctx context.Context, token *entities.PasswordResetToken) error
+	GetByHash(ctx context.Context, tokenHash string) (*entities.PasswordResetToken, error)
+	// MarkUsed consome o token. Retorna false se ele já tinha sido usado.
+	MarkUsed(ctx context.Context, id uuid.UUID) (bool, error)
+	InvalidateAllForUser(ctx context.Context, userID uuid.UUID) error
+	CountSince(ctx context.Context, userID uuid.UUID, since time.Time) (int, error)
+}
@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type RecoveryCodeRepository interface {
+	// ReplaceForUser apaga os códigos atuais do usuário e grava os novos.
+	ReplaceForUser(ctx context.Context, userID uuid.UUID, codeHashes []string) error
+	// Use consome um código. Retorna false se ele não existe ou já foi usado.
+	Use(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error)
+	DeleteForUser(ctx context.Context, userID uuid.UUID) error
+}
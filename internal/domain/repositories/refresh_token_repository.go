@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/google/uuid"
+)
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *entities.RefreshToken) error
+	GetByHash(ctx context.Context, tokenHash string) (*entities.RefreshToken, error)
+	// Rotate revoga o token atual e grava o seu sucessor. Retorna false se o
+	// token atual já tinha sido revogado (ou seja, está sendo reutilizado).
+	Rotate(ctx context.Context, currentID uuid.UUID, next *entities.RefreshToken) (bool, error)
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) error
+	RevokeAllForUser(ctx context.Context, userID uuid.UUID) error
+}
@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/google/uuid"
+)
+
+type SessionRepository interface {
+	Create(ctx context.Context, session *entities.Session) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Session, error)
+	ListActiveByUser(ctx context.Context, userID uuid.UUID) ([]*entities.Session, error)
+	Touch(ctx context.Context, id uuid.UUID, ip, userAgent string) error
+	Revoke(ctx context.Context, id uuid.UUID) error
+	RevokeAllForUser(ctx context.Context, userID uuid.UUID) error
+}
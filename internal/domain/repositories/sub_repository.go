@@ -15,4 +15,10 @@ type SubRepository interface {
 	Delete(ctx context.Context, id uuid.UUID) error
 	List(ctx context.Context, limit, offset int) ([]*entities.Sub, error)
 	GetTrending(ctx context.Context, limit int) ([]*entities.Sub, error)
+	IsModerator(ctx context.Context, subID, userID uuid.UUID) (bool, error)
+	// Ban é idempotente: banir de novo só atualiza o motivo.
+	Ban(ctx context.Context, ban *entities.SubBan) error
+	// Unban retorna false se o usuário não estava banido.
+	Unban(ctx context.Context, subID, userID uuid.UUID) (bool, error)
+	IsBanned(ctx context.Context, subID, userID uuid.UUID) (bool, error)
 }
\ No newline at end of file
@@ -0,0 +1,12 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+)
+
+type UserIdentityRepository interface {
+	Create(ctx context.Context, identity *entities.UserIdentity) error
+	GetByProviderSubject(ctx context.Context, provider, subject string) (*entities.UserIdentity, error)
+}
@@ -2,16 +2,23 @@ package repositories
 
 import (
 	"context"
+	"errors"
 
 	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
 	"github.com/google/uuid"
 )
 
+// ErrUsernameTaken é devolvido quando o nome já pertence a outra conta,
+// inclusive quando só o índice único do banco percebe a colisão.
+var ErrUsernameTaken = errors.New("username already exists")
+
 type UserRepository interface {
 	Create(ctx context.Context, user *entities.User) error
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.User, error)
 	GetByEmail(ctx context.Context, email string) (*entities.User, error)
+	GetByUsername(ctx context.Context, username string) (*entities.User, error)
 	Update(ctx context.Context, user *entities.User) error
+	UseTOTPStep(ctx context.Context, id uuid.UUID, step int64) (bool, error)
 	CheckEmailExists(ctx context.Context, email string) (bool, error)
 	CheckUsernameExists(ctx context.Context, username string) (bool, error)
-}
\ No newline at end of file
+}
@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/google/uuid"
+)
+
+type UsernameHistoryRepository interface {
+	// ChangeUsername troca o nome do usuário e registra a troca na mesma transação
+	ChangeUsername(ctx context.Context, change *entities.UsernameChange) error
+	GetLastByUser(ctx context.Context, userID uuid.UUID) (*entities.UsernameChange, error)
+	// GetRecentByOldUsername busca a troca mais recente que liberou username
+	// (sem diferenciar maiúsculas) depois de since
+	GetRecentByOldUsername(ctx context.Context, username string, since time.Time) (*entities.UsernameChange, error)
+}
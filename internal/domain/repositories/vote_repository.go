@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// VoteRepository grava os votos e mantém os contadores upvotes/downvotes de
+// posts e comentários junto com a tabela votes. Fora dele, só o purge de
+// contas (AccountRepository.Purge) apaga votos, descontando os contadores na
+// mesma transação.
+type VoteRepository interface {
+	// SetPostVote grava o voto de userID (1, -1, ou 0 para remover) e retorna
+	// false se o post não existe
+	SetPostVote(ctx context.Context, postID, userID uuid.UUID, dir int) (bool, error)
+	SetCommentVote(ctx context.Context, commentID, userID uuid.UUID, dir int) (bool, error)
+	// GetPostVotes devolve a direção do voto de userID em cada post votado
+	GetPostVotes(ctx context.Context, userID uuid.UUID, postIDs []uuid.UUID) (map[uuid.UUID]int, error)
+	GetCommentVotes(ctx context.Context, userID uuid.UUID, commentIDs []uuid.UUID) (map[uuid.UUID]int, error)
+}
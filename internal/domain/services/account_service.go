@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/repositories"
+	"github.com/elaurentium/exilium-blog-backend/internal/infra/auth"
+	"github.com/google/uuid"
+)
+
+// Tamanho das páginas usadas para montar o export e do lote do purge
+const (
+	exportPageSize = 100
+	purgeBatchSize = 100
+)
+
+// AccountService cuida da exclusão da conta pelo próprio usuário e do export
+// dos seus dados.
+type AccountService struct {
+	accountRepo repositories.AccountRepository
+	userRepo    repositories.UserRepository
+	postRepo    repositories.PostRepository
+	commentRepo repositories.CommentRepository
+	hasher      auth.PasswordHasher
+	userService *UserService
+}
+
+func NewAccountService(
+	accountRepo repositories.AccountRepository,
+	userRepo repositories.UserRepository,
+	postRepo repositories.PostRepository,
+	commentRepo repositories.CommentRepository,
+	hasher auth.PasswordHasher,
+	userService *UserService,
+) *AccountService {
+	return &AccountService{
+		accountRepo: accountRepo,
+		userRepo:    userRepo,
+		postRepo:    postRepo,
+		commentRepo: commentRepo,
+		hasher:      hasher,
+		userService: userService,
+	}
+}
+
+// DeleteAccount marca a conta como apagada e derruba todas as sessões. Posts
+// e comentários passam a aparecer como "[deleted]" de imediato; a remoção
+// definitiva fica para o PurgeDeleted, depois do período de carência.
+func (s *AccountService) DeleteAccount(ctx context.Context, userID uuid.UUID, password string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil || user == nil {
+		return errors.New("user not found")
+	}
+
+	// Contas criadas pelo login social não têm senha
+	if user.HashedPassword != "" && !s.hasher.VerifyPassword(password, user.HashedPassword, user.Salt) {
+		return errors.New("password is incorrect")
+	}
+
+	if err := s.accountRepo.SoftDelete(ctx, userID); err != nil {
+		return err
+	}
+
+	return s.userService.LogoutAll(ctx, userID)
+}
+
+// ExportData reúne o perfil e tudo o que o usuário publicou, votou ou assinou.
+func (s *AccountService) ExportData(ctx context.Context, userID uuid.UUID) (*entities.UserDataExport, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil || user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	export := &entities.UserDataExport{
+		GeneratedAt: time.Now(),
+		Profile:     user,
+	}
+
+	for offset := 0; ; offset += exportPageSize {
+		posts, err := s.postRepo.GetByUser(ctx, userID, exportPageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		export.Posts = append(export.Posts, posts...)
+		if len(posts) < exportPageSize {
+			break
+		}
+	}
+
+	for offset := 0; ; offset += exportPageSize {
+		comments, err := s.commentRepo.GetByUser(ctx, userID, exportPageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		export.Comments = append(export.Comments, comments...)
+		if len(comments) < exportPageSize {
+			break
+		}
+	}
+
+	if export.Votes, err = s.accountRepo.ListVotes(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if export.Subscriptions, err = s.accountRepo.ListSubscriptions(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
+
+// PurgeDeleted remove de vez as contas apagadas há mais de grace e devolve
+// quantas foram removidas.
+func (s *AccountService) PurgeDeleted(ctx context.Context, grace time.Duration) (int, error) {
+	purged := 0
+	for {
+		ids, err := s.accountRepo.ListDeletedBefore(ctx, time.Now().Add(-grace), purgeBatchSize)
+		if err != nil {
+			return purged, err
+		}
+
+		for _, id := range ids {
+			if err := s.accountRepo.Purge(ctx, id); err != nil {
+				return purged, err
+			}
+			purged++
+		}
+
+		if len(ids) < purgeBatchSize {
+			return purged, nil
+		}
+	}
+}
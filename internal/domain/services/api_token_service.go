@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/repositories"
+	"github.com/elaurentium/exilium-blog-backend/internal/infra/auth"
+	"github.com/google/uuid"
+)
+
+const (
+	maxAPITokensPerUser = 20
+	// Só os primeiros caracteres ficam visíveis na listagem
+	apiTokenDisplayLength = 12
+)
+
+type APITokenService struct {
+	tokenRepo repositories.APITokenRepository
+	userRepo  repositories.UserRepository
+}
+
+func NewAPITokenService(tokenRepo repositories.APITokenRepository, userRepo repositories.UserRepository) *APITokenService {
+	return &APITokenService{
+		tokenRepo: tokenRepo,
+		userRepo:  userRepo,
+	}
+}
+
+// Create gera um novo token. O valor em claro só é devolvido aqui; no banco
+// fica apenas o hash.
+func (s *APITokenService) Create(ctx context.Context, userID uuid.UUID, name string, scopes []string, expiresAt *time.Time) (*entities.APIToken, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", errors.New("token name is required")
+	}
+
+	if len(scopes) == 0 {
+		return nil, "", errors.New("at least one scope is required")
+	}
+	for _, scope := range scopes {
+		if !isValidScope(scope) {
+			return nil, "", errors.New("invalid scope: " + scope)
+		}
+	}
+
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, "", errors.New("expiration must be in the future")
+	}
+
+	secret, _, err := auth.GenerateOpaqueToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+	raw := auth.APITokenPrefix + secret
+
+	token := &entities.APIToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      name,
+		Prefix:    raw[:apiTokenDisplayLength],
+		TokenHash: auth.HashToken(raw),
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}
+
+	// O limite conta só tokens ativos e é aplicado pelo repositório junto
+	// com a inserção, para valer também entre requisições simultâneas
+	created, err := s.tokenRepo.Create(ctx, token, maxAPITokensPerUser)
+	if err != nil {
+		return nil, "", err
+	}
+	if !created {
+		return nil, "", errors.New("api token limit reached")
+	}
+
+	return token, raw, nil
+}
+
+func (s *APITokenService) List(ctx context.Context, userID uuid.UUID) ([]*entities.APIToken, error) {
+	return s.tokenRepo.ListByUser(ctx, userID)
+}
+
+func (s *APITokenService) Revoke(ctx context.Context, userID, tokenID uuid.UUID) error {
+	revoked, err := s.tokenRepo.Revoke(ctx, tokenID, userID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return errors.New("api token not found")
+	}
+	return nil
+}
+
+// Authenticate valida o token apresentado e retorna o token e o seu dono.
+func (s *APITokenService) Authenticate(ctx context.Context, raw string) (*entities.APIToken, *entities.User, error) {
+	token, err := s.tokenRepo.GetByHash(ctx, auth.HashToken(raw))
+	if err != nil {
+		return nil, nil, err
+	}
+	if token == nil || token.RevokedAt != nil {
+		return nil, nil, errors.New("invalid api token")
+	}
+	if token.IsExpired(time.Now()) {
+		return nil, nil, errors.New("api token has expired")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, token.UserID)
+	if err != nil || !user.IsActive {
+		return nil, nil, errors.New("invalid api token")
+	}
+
+	// Falhar ao registrar o uso não deve bloquear a requisição
+	_ = s.tokenRepo.TouchLastUsed(ctx, token.ID)
+
+	return token, user, nil
+}
+
+func isValidScope(scope string) bool {
+	for _, s := range entities.APITokenScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
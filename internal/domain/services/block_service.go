@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/repositories"
+	"github.com/google/uuid"
+)
+
+// BlockService cuida de bloquear e silenciar outros usuários.
+type BlockService struct {
+	blockRepo  repositories.BlockRepository
+	followRepo repositories.FollowRepository
+	userRepo   repositories.UserRepository
+}
+
+func NewBlockService(
+	blockRepo repositories.BlockRepository,
+	followRepo repositories.FollowRepository,
+	userRepo repositories.UserRepository,
+) *BlockService {
+	return &BlockService{
+		blockRepo:  blockRepo,
+		followRepo: followRepo,
+		userRepo:   userRepo,
+	}
+}
+
+// Block também desfaz os follows entre os dois usuários, nos dois sentidos.
+func (s *BlockService) Block(ctx context.Context, userID uuid.UUID, username, kind string) error {
+	target, err := s.findTarget(ctx, userID, username)
+	if err != nil {
+		return err
+	}
+
+	if err := s.blockRepo.Create(ctx, userID, target.ID, kind); err != nil {
+		return err
+	}
+
+	if kind != entities.BlockKindBlock {
+		return nil
+	}
+
+	if err := s.followRepo.Unfollow(ctx, userID, target.ID); err != nil {
+		return err
+	}
+	return s.followRepo.Unfollow(ctx, target.ID, userID)
+}
+
+func (s *BlockService) Unblock(ctx context.Context, userID uuid.UUID, username, kind string) error {
+	target, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil || target == nil {
+		return errors.New("user not found")
+	}
+
+	return s.blockRepo.Delete(ctx, userID, target.ID, kind)
+}
+
+func (s *BlockService) ListBlocks(ctx context.Context, userID uuid.UUID) ([]*entities.Block, error) {
+	return s.blockRepo.ListByUser(ctx, userID)
+}
+
+func (s *BlockService) findTarget(ctx context.Context, userID uuid.UUID, username string) (*entities.User, error) {
+	target, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil || target == nil || !target.IsActive {
+		return nil, errors.New("user not found")
+	}
+
+	if target.ID == userID {
+		return nil, errors.New("you cannot block or mute yourself")
+	}
+
+	return target, nil
+}
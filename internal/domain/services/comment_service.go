@@ -13,18 +13,27 @@ import (
 type CommentService struct {
 	commentRepo repositories.CommentRepository
 	postRepo    repositories.PostRepository
+	subRepo     repositories.SubRepository
 	userRepo    repositories.UserRepository
+	blockRepo   repositories.BlockRepository
+	voteRepo    repositories.VoteRepository
 }
 
 func NewCommentService(
 	commentRepo repositories.CommentRepository,
 	postRepo repositories.PostRepository,
+	subRepo repositories.SubRepository,
 	userRepo repositories.UserRepository,
+	blockRepo repositories.BlockRepository,
+	voteRepo repositories.VoteRepository,
 ) *CommentService {
 	return &CommentService{
 		commentRepo: commentRepo,
 		postRepo:    postRepo,
+		subRepo:     subRepo,
 		userRepo:    userRepo,
+		blockRepo:   blockRepo,
+		voteRepo:    voteRepo,
 	}
 }
 
@@ -37,7 +46,7 @@ func (s *CommentService) CreateComment(
 ) (*entities.Comment, error) {
 	// Verificar se o post existe
 	post, err := s.postRepo.GetByID(ctx, postID)
-	if err != nil {
+	if err != nil || post == nil {
 		return nil, errors.New("post not found")
 	}
 
@@ -52,12 +61,31 @@ func (s *CommentService) CreateComment(
 		return nil, errors.New("user not found")
 	}
 
+	banned, err := s.subRepo.IsBanned(ctx, post.SubID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if banned {
+		return nil, errors.New("you are banned from this sub")
+	}
+
 	// Verificar se o comentário pai existe, se houver
+	repliedTo := post.UserID
 	if parentID != nil {
-		_, err = s.commentRepo.GetByID(ctx, *parentID)
-		if err != nil {
+		parent, err := s.commentRepo.GetByID(ctx, *parentID)
+		if err != nil || parent == nil || parent.PostID != postID {
 			return nil, errors.New("parent comment not found")
 		}
+		repliedTo = parent.UserID
+	}
+
+	// Quem foi bloqueado não pode responder ao post nem aos comentários de quem o bloqueou
+	blocked, err := s.blockRepo.IsBlocked(ctx, repliedTo, userID)
+	if err != nil {
+		return nil, err
+	}
+	if blocked {
+		return nil, errors.New("you cannot reply to this user")
 	}
 
 	now := time.Now()
@@ -92,7 +120,7 @@ func (s *CommentService) UpdateComment(
 	content string,
 ) (*entities.Comment, error) {
 	comment, err := s.commentRepo.GetByID(ctx, id)
-	if err != nil {
+	if err != nil || comment == nil {
 		return nil, errors.New("comment not found")
 	}
 
@@ -103,7 +131,7 @@ func (s *CommentService) UpdateComment(
 
 	// Verificar se o post está bloqueado
 	post, err := s.postRepo.GetByID(ctx, comment.PostID)
-	if err != nil {
+	if err != nil || post == nil {
 		return nil, errors.New("post not found")
 	}
 
@@ -124,35 +152,109 @@ func (s *CommentService) UpdateComment(
 
 func (s *CommentService) DeleteComment(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
 	comment, err := s.commentRepo.GetByID(ctx, id)
-	if err != nil {
+	if err != nil || comment == nil {
 		return errors.New("comment not found")
 	}
 
-	// Verificar se o usuário é o proprietário do comentário
+	// O autor pode apagar o próprio comentário; moderadores, qualquer um
 	if comment.UserID != userID {
-		// Alternativamente, poderia verificar se o usuário é moderador ou admin
-		return errors.New("user not authorized to delete this comment")
+		allowed, err := userHasPermission(ctx, s.userRepo, userID, entities.PermissionCommentDeleteAny)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return errors.New("user not authorized to delete this comment")
+		}
 	}
 
 	return s.commentRepo.Delete(ctx, id)
 }
 
-func (s *CommentService) UpvoteComment(ctx context.Context, commentID uuid.UUID, userID uuid.UUID) error {
-	return s.commentRepo.UpvoteComment(ctx, commentID, userID)
+// VoteComment funciona como PostService.VotePost.
+func (s *CommentService) VoteComment(ctx context.Context, commentID uuid.UUID, userID uuid.UUID, dir int) (*entities.Comment, error) {
+	if dir < -1 || dir > 1 {
+		return nil, errors.New("vote direction must be 1, 0 or -1")
+	}
+
+	found, err := s.voteRepo.SetCommentVote(ctx, commentID, userID, dir)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, errors.New("comment not found")
+	}
+
+	comment, err := s.commentRepo.GetByID(ctx, commentID)
+	if err != nil || comment == nil {
+		return nil, errors.New("comment not found")
+	}
+	comment.ViewerVote = &dir
+
+	return comment, nil
+}
+
+// As listagens escondem os autores que o leitor bloqueou ou silenciou e
+// trazem o voto do leitor em ViewerVote; visitantes anônimos passam uuid.Nil.
+// Sem sort, vale a ordenação sugerida no post.
+func (s *CommentService) GetCommentsByPost(ctx context.Context, postID, viewerID uuid.UUID, sort string, limit, offset int) ([]*entities.Comment, error) {
+	sort, err := s.commentSort(ctx, postID, sort)
+	if err != nil {
+		return nil, err
+	}
+
+	comments, err := s.commentRepo.GetByPost(ctx, postID, viewerID, sort, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return s.withViewerVotes(ctx, viewerID, comments)
 }
 
-func (s *CommentService) DownvoteComment(ctx context.Context, commentID uuid.UUID, userID uuid.UUID) error {
-	return s.commentRepo.DownvoteComment(ctx, commentID, userID)
+func (s *CommentService) GetReplies(ctx context.Context, parentID, viewerID uuid.UUID, sort string, limit, offset int) ([]*entities.Comment, error) {
+	if sort == "" {
+		parent, err := s.commentRepo.GetByID(ctx, parentID)
+		if err != nil || parent == nil {
+			return nil, errors.New("comment not found")
+		}
+		if sort, err = s.commentSort(ctx, parent.PostID, sort); err != nil {
+			return nil, err
+		}
+	}
+
+	replies, err := s.commentRepo.GetReplies(ctx, parentID, viewerID, sort, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return s.withViewerVotes(ctx, viewerID, replies)
 }
 
-func (s *CommentService) RemoveVote(ctx context.Context, commentID uuid.UUID, userID uuid.UUID) error {
-	return s.commentRepo.RemoveVote(ctx, commentID, userID)
+// commentSort devolve a ordenação pedida ou, sem ela, a sugerida no post
+// (best quando o post não tem sugestão).
+func (s *CommentService) commentSort(ctx context.Context, postID uuid.UUID, sort string) (string, error) {
+	if sort != "" {
+		return sort, nil
+	}
+
+	post, err := s.postRepo.GetByID(ctx, postID)
+	if err != nil || post == nil {
+		return "", errors.New("post not found")
+	}
+
+	return postCommentSort(post, sort), nil
 }
 
-func (s *CommentService) GetCommentsByPost(ctx context.Context, postID uuid.UUID, limit, offset int) ([]*entities.Comment, error) {
-	return s.commentRepo.GetByPost(ctx, postID, limit, offset)
+func postCommentSort(post *entities.Post, sort string) string {
+	if sort != "" {
+		return sort
+	}
+	if post.SuggestedSort != nil {
+		return *post.SuggestedSort
+	}
+	return entities.CommentSortBest
 }
 
-func (s *CommentService) GetReplies(ctx context.Context, parentID uuid.UUID, limit, offset int) ([]*entities.Comment, error) {
-	return s.commentRepo.GetReplies(ctx, parentID, limit, offset)
+func (s *CommentService) withViewerVotes(ctx context.Context, viewerID uuid.UUID, comments []*entities.Comment) ([]*entities.Comment, error) {
+	if err := attachCommentVotes(ctx, s.voteRepo, viewerID, comments...); err != nil {
+		return nil, err
+	}
+	return comments, nil
 }
\ No newline at end of file
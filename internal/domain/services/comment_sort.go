@@ -0,0 +1,25 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+)
+
+// ParseCommentSort valida ?sort= dos comentários. Vazio significa "use a
+// ordenação sugerida no post".
+func ParseCommentSort(sort string) (string, error) {
+	sort = strings.ToLower(strings.TrimSpace(sort))
+	if sort == "" {
+		return "", nil
+	}
+
+	for _, s := range entities.CommentSorts {
+		if s == sort {
+			return sort, nil
+		}
+	}
+
+	return "", errors.New("sort must be one of " + strings.Join(entities.CommentSorts, ", "))
+}
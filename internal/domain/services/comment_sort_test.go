@@ -0,0 +1,63 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+)
+
+func TestParseCommentSort(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{input: "", expected: ""},
+		{input: "  ", expected: ""},
+		{input: "best", expected: entities.CommentSortBest},
+		{input: " Top ", expected: entities.CommentSortTop},
+		{input: "NEW", expected: entities.CommentSortNew},
+		{input: "old", expected: entities.CommentSortOld},
+		{input: "controversial", expected: entities.CommentSortControversial},
+		{input: "qa", expected: entities.CommentSortQA},
+		{input: "hot", wantErr: true},
+		{input: "random", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		got, err := ParseCommentSort(tc.input)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("ParseCommentSort(%q) = %q, want an error", tc.input, got)
+			}
+			continue
+		}
+		if err != nil || got != tc.expected {
+			t.Errorf("ParseCommentSort(%q) = %q, %v; want %q", tc.input, got, err, tc.expected)
+		}
+	}
+}
+
+func TestPostCommentSort(t *testing.T) {
+	qa := entities.CommentSortQA
+
+	tests := []struct {
+		name      string
+		suggested *string
+		requested string
+		expected  string
+	}{
+		{name: "default", expected: entities.CommentSortBest},
+		{name: "suggested by the post", suggested: &qa, expected: entities.CommentSortQA},
+		{name: "requested wins over suggested", suggested: &qa, requested: entities.CommentSortNew, expected: entities.CommentSortNew},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			post := &entities.Post{SuggestedSort: tc.suggested}
+			if got := postCommentSort(post, tc.requested); got != tc.expected {
+				t.Fatalf("postCommentSort = %q, want %q", got, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultTreeDepth    = 5
+	maxTreeDepth        = 10
+	defaultTreeLimit    = 20
+	maxTreeLimit        = 100
+	defaultTreeChildren = 5
+	maxTreeChildren     = 50
+	maxTreeContext      = 8
+)
+
+// ErrInvalidContinuation indica um ?continue= que não veio de um "more".
+var ErrInvalidContinuation = errors.New("invalid continuation token")
+
+// CommentTreeOptions são os parâmetros de GET /posts/:id/comments/tree.
+// Valores zerados usam o padrão. Continuation retoma um ramo recolhido;
+// CommentID pede o permalink de um comentário com Context ancestrais.
+type CommentTreeOptions struct {
+	Sort         string
+	Depth        int
+	Limit        int
+	Children     int
+	Continuation string
+	CommentID    *uuid.UUID
+	Context      int
+}
+
+// GetCommentTree monta a árvore de comentários do post no servidor. Ramos
+// cortados por profundidade ou pelo limite de filhos trazem em More quantas
+// respostas faltam e o token para buscá-las.
+func (s *CommentService) GetCommentTree(ctx context.Context, postID, viewerID uuid.UUID, opts CommentTreeOptions) (*entities.CommentTree, error) {
+	post, err := s.postRepo.GetByID(ctx, postID)
+	if err != nil || post == nil {
+		return nil, errors.New("post not found")
+	}
+
+	query := entities.CommentTreeQuery{
+		PostID:   postID,
+		ViewerID: viewerID,
+		Sort:     postCommentSort(post, opts.Sort),
+		Depth:    clampTreeParam(opts.Depth, defaultTreeDepth, maxTreeDepth),
+		Limit:    clampTreeParam(opts.Limit, defaultTreeLimit, maxTreeLimit),
+		Children: clampTreeParam(opts.Children, defaultTreeChildren, maxTreeChildren),
+	}
+
+	if opts.CommentID != nil {
+		return s.commentPermalink(ctx, query, *opts.CommentID, opts.Context)
+	}
+
+	if opts.Continuation != "" {
+		parentID, offset, err := decodeCommentContinuation(opts.Continuation, query.PostID, query.Sort)
+		if err != nil {
+			return nil, err
+		}
+		if parentID != uuid.Nil {
+			query.ParentID = &parentID
+		}
+		query.Offset = offset
+	}
+
+	nodes, err := s.commentRepo.GetTree(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachTreeVotes(ctx, viewerID, nodes); err != nil {
+		return nil, err
+	}
+
+	comments, more := buildCommentTree(query, nodes)
+	return &entities.CommentTree{Comments: comments, More: more}, nil
+}
+
+// commentPermalink devolve o comentário com suas respostas (até Depth níveis
+// contando ele mesmo) pendurado no ramo de até ancestorCount ancestrais.
+func (s *CommentService) commentPermalink(ctx context.Context, query entities.CommentTreeQuery, commentID uuid.UUID, ancestorCount int) (*entities.CommentTree, error) {
+	// Um comentário apagado que ainda segura respostas continua alcançável,
+	// como "[deleted]", do mesmo jeito que aparece na árvore
+	comment, err := s.commentRepo.GetTreeComment(ctx, commentID)
+	if err != nil || comment == nil || comment.PostID != query.PostID {
+		return nil, errors.New("comment not found")
+	}
+
+	// Quem bloqueou ou silenciou o autor do comentário, ou de um ancestral,
+	// não o vê na árvore e também não o alcança pelo permalink
+	visible, err := s.commentRepo.IsVisibleTo(ctx, comment.ID, query.ViewerID)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, errors.New("comment not found")
+	}
+
+	// O próprio comentário ocupa o primeiro nível
+	showReplies := query.Depth > 1
+	query.ParentID = &comment.ID
+	query.Limit = query.Children
+	if showReplies {
+		query.Depth--
+	}
+
+	nodes, err := s.commentRepo.GetTree(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	focus := &entities.CommentNode{Comment: comment, Replies: []*entities.CommentNode{}}
+	if len(nodes) > 0 {
+		focus.ReplyCount = nodes[0].SiblingCount
+	}
+	if showReplies {
+		focus.Replies, focus.More = buildCommentTree(query, nodes)
+	} else {
+		nodes = nil
+		if focus.ReplyCount > 0 {
+			focus.More = &entities.CommentMore{
+				Count:        focus.ReplyCount,
+				Continuation: encodeCommentContinuation(query, comment.ID, 0),
+			}
+		}
+	}
+
+	var ancestors []*entities.CommentNode
+	if ancestorCount > 0 {
+		if ancestorCount > maxTreeContext {
+			ancestorCount = maxTreeContext
+		}
+		if ancestors, err = s.commentRepo.GetAncestors(ctx, comment.ID, ancestorCount); err != nil {
+			return nil, err
+		}
+	}
+
+	all := append(append([]*entities.CommentNode{focus}, ancestors...), nodes...)
+	if err := s.attachTreeVotes(ctx, query.ViewerID, all); err != nil {
+		return nil, err
+	}
+
+	// Os ancestrais mostram só o ramo que leva ao comentário
+	root := focus
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		ancestors[i].Replies = []*entities.CommentNode{root}
+		root = ancestors[i]
+	}
+
+	return &entities.CommentTree{Comments: []*entities.CommentNode{root}}, nil
+}
+
+// buildCommentTree liga os nós (que chegam por nível e posição) aos pais e
+// gera as continuações dos ramos incompletos.
+func buildCommentTree(query entities.CommentTreeQuery, nodes []*entities.CommentNode) ([]*entities.CommentNode, *entities.CommentMore) {
+	byID := make(map[uuid.UUID]*entities.CommentNode, len(nodes))
+	roots := []*entities.CommentNode{}
+	total := 0
+
+	for _, node := range nodes {
+		node.Replies = []*entities.CommentNode{}
+		byID[node.ID] = node
+
+		if node.Depth == 1 {
+			roots = append(roots, node)
+			total = node.SiblingCount
+			continue
+		}
+		if parent, ok := byID[*node.ParentID]; ok {
+			parent.Replies = append(parent.Replies, node)
+		}
+	}
+
+	for _, node := range nodes {
+		if hidden := node.ReplyCount - len(node.Replies); hidden > 0 {
+			node.More = &entities.CommentMore{
+				Count:        hidden,
+				Continuation: encodeCommentContinuation(query, node.ID, len(node.Replies)),
+			}
+		}
+	}
+
+	var more *entities.CommentMore
+	if hidden := total - query.Offset - len(roots); hidden > 0 {
+		parentID := uuid.Nil
+		if query.ParentID != nil {
+			parentID = *query.ParentID
+		}
+		more = &entities.CommentMore{
+			Count:        hidden,
+			Continuation: encodeCommentContinuation(query, parentID, query.Offset+len(roots)),
+		}
+	}
+
+	return roots, more
+}
+
+func (s *CommentService) attachTreeVotes(ctx context.Context, viewerID uuid.UUID, nodes []*entities.CommentNode) error {
+	comments := make([]*entities.Comment, len(nodes))
+	for i, node := range nodes {
+		comments[i] = node.Comment
+	}
+	return attachCommentVotes(ctx, s.voteRepo, viewerID, comments...)
+}
+
+func clampTreeParam(value, def, max int) int {
+	if value <= 0 {
+		return def
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
+
+// O token de continuação é opaco para o cliente:
+// "<post_id>:<sort>:<parent_id>:<offset>" em base64, com uuid.Nil para os
+// comentários de primeiro nível. O offset só vale para o post e a ordenação
+// em que foi gerado, então os dois vão no token e são conferidos na volta.
+func encodeCommentContinuation(query entities.CommentTreeQuery, parentID uuid.UUID, offset int) string {
+	raw := query.PostID.String() + ":" + query.Sort + ":" + parentID.String() + ":" + strconv.Itoa(offset)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCommentContinuation(token string, postID uuid.UUID, sort string) (uuid.UUID, int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return uuid.Nil, 0, ErrInvalidContinuation
+	}
+
+	parts := strings.SplitN(string(raw), ":", 4)
+	if len(parts) != 4 || parts[0] != postID.String() || parts[1] != sort {
+		return uuid.Nil, 0, ErrInvalidContinuation
+	}
+
+	parentID, err := uuid.Parse(parts[2])
+	if err != nil {
+		return uuid.Nil, 0, ErrInvalidContinuation
+	}
+
+	offset, err := strconv.Atoi(parts[3])
+	if err != nil || offset < 0 {
+		return uuid.Nil, 0, ErrInvalidContinuation
+	}
+
+	return parentID, offset, nil
+}
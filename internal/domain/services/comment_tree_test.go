@@ -0,0 +1,362 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/repositories"
+	"github.com/google/uuid"
+)
+
+type wantMore struct {
+	count    int
+	parentID uuid.UUID
+	offset   int
+}
+
+func treeNode(id uuid.UUID, parentID *uuid.UUID, depth, siblings, replies int) *entities.CommentNode {
+	return &entities.CommentNode{
+		Comment:      &entities.Comment{ID: id, ParentID: parentID},
+		Depth:        depth,
+		SiblingCount: siblings,
+		ReplyCount:   replies,
+	}
+}
+
+func TestBuildCommentTree(t *testing.T) {
+	postID := uuid.New()
+	a, b, a1, a1x := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	parent := uuid.New()
+
+	tests := []struct {
+		name        string
+		query       entities.CommentTreeQuery
+		nodes       []*entities.CommentNode
+		wantRoots   []uuid.UUID
+		wantReplies map[uuid.UUID][]uuid.UUID
+		wantMore    map[uuid.UUID]wantMore
+		wantTopMore *wantMore
+	}{
+		{
+			name:      "empty post",
+			query:     entities.CommentTreeQuery{PostID: postID, Sort: entities.CommentSortBest},
+			wantRoots: []uuid.UUID{},
+		},
+		{
+			name:  "first page with branches cut by children and depth",
+			query: entities.CommentTreeQuery{PostID: postID, Sort: entities.CommentSortBest},
+			nodes: []*entities.CommentNode{
+				treeNode(a, nil, 1, 3, 2),
+				treeNode(b, nil, 1, 3, 0),
+				treeNode(a1, &a, 2, 2, 1),
+			},
+			wantRoots:   []uuid.UUID{a, b},
+			wantReplies: map[uuid.UUID][]uuid.UUID{a: {a1}},
+			wantMore: map[uuid.UUID]wantMore{
+				a:  {count: 1, parentID: a, offset: 1},
+				a1: {count: 1, parentID: a1, offset: 0},
+			},
+			wantTopMore: &wantMore{count: 1, parentID: uuid.Nil, offset: 2},
+		},
+		{
+			name:  "continuation of a branch",
+			query: entities.CommentTreeQuery{PostID: postID, Sort: entities.CommentSortNew, ParentID: &parent, Offset: 2},
+			nodes: []*entities.CommentNode{
+				treeNode(a, &parent, 1, 5, 1),
+				treeNode(a1x, &a, 2, 1, 0),
+			},
+			wantRoots:   []uuid.UUID{a},
+			wantReplies: map[uuid.UUID][]uuid.UUID{a: {a1x}},
+			wantTopMore: &wantMore{count: 2, parentID: parent, offset: 3},
+		},
+		{
+			name:  "last page has no more",
+			query: entities.CommentTreeQuery{PostID: postID, Sort: entities.CommentSortTop, Offset: 4},
+			nodes: []*entities.CommentNode{
+				treeNode(a, nil, 1, 5, 0),
+			},
+			wantRoots: []uuid.UUID{a},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			roots, more := buildCommentTree(tc.query, tc.nodes)
+
+			if got := nodeIDs(roots); !equalIDs(got, tc.wantRoots) {
+				t.Fatalf("roots = %v, want %v", got, tc.wantRoots)
+			}
+			for _, node := range tc.nodes {
+				if got := nodeIDs(node.Replies); !equalIDs(got, tc.wantReplies[node.ID]) {
+					t.Errorf("replies of %s = %v, want %v", node.ID, got, tc.wantReplies[node.ID])
+				}
+				want, ok := tc.wantMore[node.ID]
+				if !ok {
+					if node.More != nil {
+						t.Errorf("unexpected more on %s: %+v", node.ID, node.More)
+					}
+					continue
+				}
+				checkMore(t, tc.query, node.More, &want)
+			}
+			checkMore(t, tc.query, more, tc.wantTopMore)
+		})
+	}
+}
+
+func checkMore(t *testing.T, query entities.CommentTreeQuery, got *entities.CommentMore, want *wantMore) {
+	t.Helper()
+
+	if want == nil {
+		if got != nil {
+			t.Errorf("unexpected more: %+v", got)
+		}
+		return
+	}
+	if got == nil {
+		t.Fatalf("missing more, want %+v", *want)
+	}
+	if got.Count != want.count {
+		t.Errorf("more count = %d, want %d", got.Count, want.count)
+	}
+
+	parentID, offset, err := decodeCommentContinuation(got.Continuation, query.PostID, query.Sort)
+	if err != nil {
+		t.Fatalf("continuation does not decode: %v", err)
+	}
+	if parentID != want.parentID || offset != want.offset {
+		t.Errorf("continuation = (%s, %d), want (%s, %d)", parentID, offset, want.parentID, want.offset)
+	}
+}
+
+func nodeIDs(nodes []*entities.CommentNode) []uuid.UUID {
+	ids := make([]uuid.UUID, len(nodes))
+	for i, node := range nodes {
+		ids[i] = node.ID
+	}
+	return ids
+}
+
+func equalIDs(a, b []uuid.UUID) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestCommentContinuation(t *testing.T) {
+	postID, parentID := uuid.New(), uuid.New()
+	query := entities.CommentTreeQuery{PostID: postID, Sort: entities.CommentSortBest}
+	raw := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name       string
+		token      string
+		postID     uuid.UUID
+		sort       string
+		wantParent uuid.UUID
+		wantOffset int
+		wantErr    bool
+	}{
+		{
+			name:       "round trip",
+			token:      encodeCommentContinuation(query, parentID, 7),
+			postID:     postID,
+			sort:       entities.CommentSortBest,
+			wantParent: parentID,
+			wantOffset: 7,
+		},
+		{
+			name:       "top level",
+			token:      encodeCommentContinuation(query, uuid.Nil, 20),
+			postID:     postID,
+			sort:       entities.CommentSortBest,
+			wantParent: uuid.Nil,
+			wantOffset: 20,
+		},
+		{
+			name:    "other post",
+			token:   encodeCommentContinuation(query, parentID, 7),
+			postID:  uuid.New(),
+			sort:    entities.CommentSortBest,
+			wantErr: true,
+		},
+		{
+			name:    "other sort",
+			token:   encodeCommentContinuation(query, parentID, 7),
+			postID:  postID,
+			sort:    entities.CommentSortNew,
+			wantErr: true,
+		},
+		{name: "not base64", token: "!!!", postID: postID, sort: entities.CommentSortBest, wantErr: true},
+		{name: "old format", token: raw(parentID.String() + ":7"), postID: postID, sort: entities.CommentSortBest, wantErr: true},
+		{
+			name:    "negative offset",
+			token:   raw(postID.String() + ":best:" + parentID.String() + ":-1"),
+			postID:  postID,
+			sort:    entities.CommentSortBest,
+			wantErr: true,
+		},
+		{
+			name:    "invalid parent",
+			token:   raw(postID.String() + ":best:not-a-uuid:1"),
+			postID:  postID,
+			sort:    entities.CommentSortBest,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			parent, offset, err := decodeCommentContinuation(tc.token, tc.postID, tc.sort)
+			if tc.wantErr {
+				if err != ErrInvalidContinuation {
+					t.Fatalf("err = %v, want ErrInvalidContinuation", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if parent != tc.wantParent || offset != tc.wantOffset {
+				t.Errorf("got (%s, %d), want (%s, %d)", parent, offset, tc.wantParent, tc.wantOffset)
+			}
+		})
+	}
+}
+
+type fakePostRepo struct {
+	repositories.PostRepository
+	post *entities.Post
+}
+
+func (r *fakePostRepo) GetByID(ctx context.Context, id uuid.UUID) (*entities.Post, error) {
+	if id != r.post.ID {
+		return nil, nil
+	}
+	return r.post, nil
+}
+
+// fakeTreeCommentRepo guarda os comentários como o banco os devolveria:
+// GetByID ignora os apagados e GetTreeComment só os mantém, como
+// "[deleted]", enquanto houver respostas vivas.
+type fakeTreeCommentRepo struct {
+	repositories.CommentRepository
+	comments map[uuid.UUID]*entities.Comment
+	replies  map[uuid.UUID][]*entities.Comment
+}
+
+func (r *fakeTreeCommentRepo) GetByID(ctx context.Context, id uuid.UUID) (*entities.Comment, error) {
+	comment := r.comments[id]
+	if comment == nil || comment.DeletedAt != nil {
+		return nil, nil
+	}
+	return comment, nil
+}
+
+func (r *fakeTreeCommentRepo) GetTreeComment(ctx context.Context, id uuid.UUID) (*entities.Comment, error) {
+	comment := r.comments[id]
+	if comment == nil {
+		return nil, nil
+	}
+	if comment.DeletedAt == nil {
+		return comment, nil
+	}
+	for _, reply := range r.replies[id] {
+		if reply.DeletedAt == nil {
+			placeholder := *comment
+			placeholder.Content, placeholder.Author, placeholder.UserID = "[deleted]", "[deleted]", uuid.Nil
+			return &placeholder, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeTreeCommentRepo) IsVisibleTo(ctx context.Context, id, viewerID uuid.UUID) (bool, error) {
+	return true, nil
+}
+
+func (r *fakeTreeCommentRepo) GetTree(ctx context.Context, query entities.CommentTreeQuery) ([]*entities.CommentNode, error) {
+	var nodes []*entities.CommentNode
+	for _, reply := range r.replies[*query.ParentID] {
+		if reply.DeletedAt == nil {
+			nodes = append(nodes, treeNode(reply.ID, reply.ParentID, 1, 0, 0))
+		}
+	}
+	for _, node := range nodes {
+		node.SiblingCount = len(nodes)
+	}
+	return nodes, nil
+}
+
+func TestCommentPermalink(t *testing.T) {
+	post := &entities.Post{ID: uuid.New()}
+	deletedAt := time.Now()
+
+	live := &entities.Comment{ID: uuid.New(), PostID: post.ID, Content: "hello", Author: "alice"}
+	liveReply := &entities.Comment{ID: uuid.New(), PostID: post.ID, ParentID: &live.ID, Content: "reply"}
+	deleted := &entities.Comment{ID: uuid.New(), PostID: post.ID, Content: "gone", Author: "bob", DeletedAt: &deletedAt}
+	deletedReply := &entities.Comment{ID: uuid.New(), PostID: post.ID, ParentID: &deleted.ID, Content: "still here"}
+	deadBranch := &entities.Comment{ID: uuid.New(), PostID: post.ID, Content: "gone", DeletedAt: &deletedAt}
+	deadLeaf := &entities.Comment{ID: uuid.New(), PostID: post.ID, ParentID: &deadBranch.ID, DeletedAt: &deletedAt}
+
+	repo := &fakeTreeCommentRepo{
+		comments: map[uuid.UUID]*entities.Comment{},
+		replies: map[uuid.UUID][]*entities.Comment{
+			live.ID:       {liveReply},
+			deleted.ID:    {deletedReply},
+			deadBranch.ID: {deadLeaf},
+		},
+	}
+	for _, comment := range []*entities.Comment{live, liveReply, deleted, deletedReply, deadBranch, deadLeaf} {
+		repo.comments[comment.ID] = comment
+	}
+	service := NewCommentService(repo, &fakePostRepo{post: post}, nil, nil, nil, nil)
+
+	tests := []struct {
+		name        string
+		commentID   uuid.UUID
+		wantContent string
+		wantReplies []uuid.UUID
+		wantErr     bool
+	}{
+		{name: "live comment", commentID: live.ID, wantContent: "hello", wantReplies: []uuid.UUID{liveReply.ID}},
+		{name: "deleted with live replies", commentID: deleted.ID, wantContent: "[deleted]", wantReplies: []uuid.UUID{deletedReply.ID}},
+		{name: "deleted branch", commentID: deadBranch.ID, wantErr: true},
+		{name: "unknown comment", commentID: uuid.New(), wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			id := tc.commentID
+			tree, err := service.GetCommentTree(context.Background(), post.ID, uuid.Nil, CommentTreeOptions{CommentID: &id})
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("expected comment not found")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetCommentTree: %v", err)
+			}
+			if len(tree.Comments) != 1 {
+				t.Fatalf("got %d roots, want 1", len(tree.Comments))
+			}
+			focus := tree.Comments[0]
+			if focus.ID != tc.commentID || focus.Content != tc.wantContent {
+				t.Fatalf("focus = %s %q, want %s %q", focus.ID, focus.Content, tc.commentID, tc.wantContent)
+			}
+			if got := nodeIDs(focus.Replies); !equalIDs(got, tc.wantReplies) {
+				t.Fatalf("replies = %v, want %v", got, tc.wantReplies)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/repositories"
+	"github.com/elaurentium/exilium-blog-backend/internal/infra/auth"
+	"github.com/elaurentium/exilium-blog-backend/internal/infra/mailer"
+	"github.com/google/uuid"
+)
+
+// Intervalo mínimo entre dois reenvios do e-mail de verificação
+const verificationResendCooldown = time.Minute
+
+// VerificationCooldownError é retornado quando o reenvio é pedido antes de
+// passado o verificationResendCooldown do anterior.
+type VerificationCooldownError struct {
+	RetryAfter time.Duration
+}
+
+func (e *VerificationCooldownError) Error() string {
+	return fmt.Sprintf("verification email was sent recently, try again in %s", e.RetryAfter.Round(time.Second))
+}
+
+type EmailVerificationService struct {
+	userRepo  repositories.UserRepository
+	tokens    *auth.JWTService
+	mailer    mailer.Mailer
+	cooldowns auth.CooldownStore
+	verifyURL string
+}
+
+// verifyURL é a página do frontend que recebe o token via query string e
+// chama POST /auth/verify-email.
+func NewEmailVerificationService(
+	userRepo repositories.UserRepository,
+	tokens *auth.JWTService,
+	mailer mailer.Mailer,
+	cooldowns auth.CooldownStore,
+	verifyURL string,
+) *EmailVerificationService {
+	return &EmailVerificationService{
+		userRepo:  userRepo,
+		tokens:    tokens,
+		mailer:    mailer,
+		cooldowns: cooldowns,
+		verifyURL: verifyURL,
+	}
+}
+
+func (s *EmailVerificationService) SendVerificationEmail(ctx context.Context, user *entities.User) error {
+	if user.EmailVerified {
+		return errors.New("email already verified")
+	}
+
+	token, err := s.tokens.GenerateEmailVerificationToken(user.ID, user.Email)
+	if err != nil {
+		return err
+	}
+
+	link := s.verifyURL + "?token=" + url.QueryEscape(token)
+
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body:    fmt.Sprintf("Hi %s,\n\nPlease confirm your email address by opening the link below:\n\n%s\n", user.Username, link),
+	})
+}
+
+// ResendVerificationEmail envia um novo link, no máximo um a cada
+// verificationResendCooldown por usuário.
+func (s *EmailVerificationService) ResendVerificationEmail(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+	if user.EmailVerified {
+		return errors.New("email already verified")
+	}
+
+	wait, err := s.cooldowns.Start(ctx, "verification-email:"+userID.String(), verificationResendCooldown)
+	if err != nil {
+		return err
+	}
+	if wait > 0 {
+		return &VerificationCooldownError{RetryAfter: wait}
+	}
+
+	return s.SendVerificationEmail(ctx, user)
+}
+
+func (s *EmailVerificationService) VerifyEmail(ctx context.Context, token string) error {
+	claims, err := s.tokens.ValidateEmailVerificationToken(token)
+	if err != nil {
+		return errors.New("invalid or expired verification token")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	// O token só vale para o e-mail para o qual foi enviado
+	if user.Email != claims.Email {
+		return errors.New("invalid or expired verification token")
+	}
+
+	if user.EmailVerified {
+		return nil
+	}
+
+	user.EmailVerified = true
+	user.UpdatedAt = time.Now()
+
+	return s.userRepo.Update(ctx, user)
+}
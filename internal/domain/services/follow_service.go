@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/repositories"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultFeedLimit = 25
+	maxFeedLimit     = 100
+)
+
+// FollowService cuida de quem segue quem e do feed montado a partir disso.
+type FollowService struct {
+	followRepo repositories.FollowRepository
+	userRepo   repositories.UserRepository
+	postRepo   repositories.PostRepository
+	blockRepo  repositories.BlockRepository
+}
+
+func NewFollowService(
+	followRepo repositories.FollowRepository,
+	userRepo repositories.UserRepository,
+	postRepo repositories.PostRepository,
+	blockRepo repositories.BlockRepository,
+) *FollowService {
+	return &FollowService{
+		followRepo: followRepo,
+		userRepo:   userRepo,
+		postRepo:   postRepo,
+		blockRepo:  blockRepo,
+	}
+}
+
+func (s *FollowService) Follow(ctx context.Context, followerID uuid.UUID, username string) error {
+	followee, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil || followee == nil || !followee.IsActive {
+		return errors.New("user not found")
+	}
+
+	if followee.ID == followerID {
+		return errors.New("you cannot follow yourself")
+	}
+
+	blocked, err := s.blockRepo.IsBlocked(ctx, followee.ID, followerID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return errors.New("you cannot follow this user")
+	}
+
+	return s.followRepo.Follow(ctx, followerID, followee.ID)
+}
+
+func (s *FollowService) Unfollow(ctx context.Context, followerID uuid.UUID, username string) error {
+	followee, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil || followee == nil {
+		return errors.New("user not found")
+	}
+
+	return s.followRepo.Unfollow(ctx, followerID, followee.ID)
+}
+
+// Feed devolve uma página dos posts de quem o usuário segue. O cursor vem da
+// página anterior (NextCursor) e é vazio na primeira.
+func (s *FollowService) Feed(ctx context.Context, userID uuid.UUID, cursor string, limit int) (*entities.FeedPage, error) {
+	if limit <= 0 || limit > maxFeedLimit {
+		limit = defaultFeedLimit
+	}
+
+	var after *entities.PostCursor
+	if cursor != "" {
+		decoded, err := decodePostCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		after = decoded
+	}
+
+	// Um post a mais indica se existe próxima página
+	posts, err := s.postRepo.GetFollowingFeed(ctx, userID, after, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &entities.FeedPage{Posts: posts}
+	if len(posts) > limit {
+		page.Posts = posts[:limit]
+		last := page.Posts[limit-1]
+		page.NextCursor = encodePostCursor(&entities.PostCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+	if page.Posts == nil {
+		page.Posts = []*entities.Post{}
+	}
+
+	return page, nil
+}
+
+// O cursor é opaco para o cliente: "<created_at em ns>:<id>" em base64.
+func encodePostCursor(cursor *entities.PostCursor) string {
+	raw := strconv.FormatInt(cursor.CreatedAt.UnixNano(), 10) + ":" + cursor.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodePostCursor(cursor string) (*entities.PostCursor, error) {
+	invalid := errors.New("invalid cursor")
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, invalid
+	}
+
+	parts := strings.SplitN(string(raw), ":", 2)
+	if len(parts) != 2 {
+		return nil, invalid
+	}
+
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, invalid
+	}
+
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return nil, invalid
+	}
+
+	// As colunas TIMESTAMP guardam o horário em UTC
+	return &entities.PostCursor{CreatedAt: time.Unix(0, nanos).UTC(), ID: id}, nil
+}
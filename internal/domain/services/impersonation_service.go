@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/repositories"
+	"github.com/elaurentium/exilium-blog-backend/internal/infra/auth"
+	"github.com/google/uuid"
+)
+
+// ImpersonationResult traz só o perfil público do alvo: o admin não precisa
+// do e-mail nem de nenhum dado de credencial para agir como ele.
+type ImpersonationResult struct {
+	AccessToken string               `json:"access_token"`
+	ExpiresAt   time.Time            `json:"expires_at"`
+	User        *entities.PublicUser `json:"user"`
+}
+
+type ImpersonationService struct {
+	userRepo  repositories.UserRepository
+	auditRepo repositories.AuditLogRepository
+	tokens    *auth.JWTService
+}
+
+func NewImpersonationService(
+	userRepo repositories.UserRepository,
+	auditRepo repositories.AuditLogRepository,
+	tokens *auth.JWTService,
+) *ImpersonationService {
+	return &ImpersonationService{
+		userRepo:  userRepo,
+		auditRepo: auditRepo,
+		tokens:    tokens,
+	}
+}
+
+// Start emite um token para o admin agir como o usuário alvo. O motivo é
+// obrigatório e fica registrado no audit log junto com o início da sessão.
+func (s *ImpersonationService) Start(ctx context.Context, actorID, targetID uuid.UUID, reason string, client ClientInfo) (*ImpersonationResult, error) {
+	if actorID == targetID {
+		return nil, errors.New("cannot impersonate yourself")
+	}
+
+	allowed, err := userHasPermission(ctx, s.userRepo, actorID, entities.PermissionUserImpersonate)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, errors.New("user not authorized to impersonate")
+	}
+
+	target, err := s.userRepo.GetByID(ctx, targetID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	// Impersonar outro admin permitiria escalar privilégios entre admins
+	if entities.RoleAtLeast(target.Role, entities.RoleAdmin) {
+		return nil, errors.New("cannot impersonate an administrator")
+	}
+
+	token, expiresAt, err := s.tokens.GenerateImpersonationToken(target.ID, target.Role, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.record(ctx, actorID, targetID, entities.AuditImpersonationStart, map[string]string{
+		"reason":     reason,
+		"expires_at": expiresAt.Format(time.RFC3339),
+	}, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ImpersonationResult{AccessToken: token, ExpiresAt: expiresAt, User: target.Public()}, nil
+}
+
+// RecordRequest registra uma ação feita com um token de impersonação e o
+// status com que ela foi respondida.
+func (s *ImpersonationService) RecordRequest(ctx context.Context, actorID, targetID uuid.UUID, method, path string, status int, client ClientInfo) error {
+	return s.record(ctx, actorID, targetID, entities.AuditImpersonationRequest, map[string]string{
+		"method": method,
+		"path":   path,
+		"status": strconv.Itoa(status),
+	}, client)
+}
+
+func (s *ImpersonationService) ListAuditLogs(ctx context.Context, userID *uuid.UUID, limit, offset int) ([]*entities.AuditLog, error) {
+	return s.auditRepo.List(ctx, userID, limit, offset)
+}
+
+func (s *ImpersonationService) record(ctx context.Context, actorID, targetID uuid.UUID, action string, details map[string]string, client ClientInfo) error {
+	return s.auditRepo.Create(ctx, &entities.AuditLog{
+		ID:           uuid.New(),
+		ActorID:      &actorID,
+		TargetUserID: &targetID,
+		Action:       action,
+		Details:      details,
+		IP:           client.IP,
+		CreatedAt:    time.Now(),
+	})
+}
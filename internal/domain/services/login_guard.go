@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/repositories"
+	"github.com/elaurentium/exilium-blog-backend/internal/infra/auth"
+	"github.com/google/uuid"
+)
+
+// ClientInfo identifica de onde vem a requisição de login.
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// LockedError é retornado enquanto a conta ou o IP estão bloqueados por
+// excesso de tentativas.
+type LockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("too many failed login attempts, try again in %s", e.RetryAfter.Round(time.Second))
+}
+
+// LoginGuard junta o bloqueio por tentativas e o registro dos eventos de
+// autenticação que os administradores consultam.
+type LoginGuard struct {
+	throttle  *auth.LoginThrottle
+	eventRepo repositories.AuthEventRepository
+}
+
+func NewLoginGuard(throttle *auth.LoginThrottle, eventRepo repositories.AuthEventRepository) *LoginGuard {
+	return &LoginGuard{throttle: throttle, eventRepo: eventRepo}
+}
+
+func (g *LoginGuard) Check(ctx context.Context, email string, client ClientInfo) error {
+	wait, err := g.throttle.Check(ctx, email, client.IP)
+	if err != nil {
+		return err
+	}
+	if wait > 0 {
+		return &LockedError{RetryAfter: wait}
+	}
+	return nil
+}
+
+// Failure registra a falha e retorna LockedError se ela disparou um bloqueio.
+func (g *LoginGuard) Failure(ctx context.Context, eventType, email string, userID *uuid.UUID, client ClientInfo) error {
+	wait, err := g.throttle.RecordFailure(ctx, email, client.IP)
+	if err != nil {
+		return err
+	}
+
+	g.record(ctx, eventType, email, userID, client)
+	if wait > 0 {
+		g.record(ctx, entities.AuthEventLoginLocked, email, userID, client)
+		return &LockedError{RetryAfter: wait}
+	}
+	return nil
+}
+
+func (g *LoginGuard) Success(ctx context.Context, user *entities.User, client ClientInfo) error {
+	if err := g.throttle.RecordSuccess(ctx, user.Email); err != nil {
+		return err
+	}
+
+	g.record(ctx, entities.AuthEventLoginSucceeded, user.Email, &user.ID, client)
+	return nil
+}
+
+func (g *LoginGuard) ListEvents(ctx context.Context, userID *uuid.UUID, limit, offset int) ([]*entities.AuthEvent, error) {
+	return g.eventRepo.List(ctx, userID, limit, offset)
+}
+
+func (g *LoginGuard) record(ctx context.Context, eventType, email string, userID *uuid.UUID, client ClientInfo) {
+	// O log de auditoria não pode derrubar o login
+	_ = g.eventRepo.Create(ctx, &entities.AuthEvent{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Email:     email,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+		Type:      eventType,
+		CreatedAt: time.Now(),
+	})
+}
@@ -0,0 +1,267 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/repositories"
+	"github.com/elaurentium/exilium-blog-backend/internal/infra/auth"
+	"github.com/google/uuid"
+)
+
+var usernameInvalidChars = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// OIDCLogin é o início do login social: a URL do provedor e o state
+// assinado que o handler guarda em cookie até o callback.
+type OIDCLogin struct {
+	AuthURL    string
+	StateToken string
+}
+
+type OIDCService struct {
+	providers    map[string]*auth.OIDCProvider
+	identityRepo repositories.UserIdentityRepository
+	userRepo     repositories.UserRepository
+	historyRepo  repositories.UsernameHistoryRepository
+	userService  *UserService
+	tokens       *auth.JWTService
+}
+
+func NewOIDCService(
+	providers []*auth.OIDCProvider,
+	identityRepo repositories.UserIdentityRepository,
+	userRepo repositories.UserRepository,
+	historyRepo repositories.UsernameHistoryRepository,
+	userService *UserService,
+	tokens *auth.JWTService,
+) *OIDCService {
+	byName := make(map[string]*auth.OIDCProvider, len(providers))
+	for _, provider := range providers {
+		byName[provider.Name()] = provider
+	}
+
+	return &OIDCService{
+		providers:    byName,
+		identityRepo: identityRepo,
+		userRepo:     userRepo,
+		historyRepo:  historyRepo,
+		userService:  userService,
+		tokens:       tokens,
+	}
+}
+
+func (s *OIDCService) Begin(ctx context.Context, providerName string) (*OIDCLogin, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, errors.New("unknown identity provider")
+	}
+
+	state, _, err := auth.GenerateOpaqueToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, _, err := auth.GenerateOpaqueToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	verifier, challenge, err := auth.GeneratePKCE()
+	if err != nil {
+		return nil, err
+	}
+
+	stateToken, err := s.tokens.GenerateOIDCStateToken(providerName, state, nonce, verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OIDCLogin{AuthURL: authURL, StateToken: stateToken}, nil
+}
+
+// Callback valida o state, troca o código e abre a sessão do usuário ligado
+// à identidade externa, ligando ou criando a conta no primeiro acesso.
+func (s *OIDCService) Callback(ctx context.Context, providerName, code, state, stateToken string, client ClientInfo) (*LoginResult, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, errors.New("unknown identity provider")
+	}
+
+	claims, err := s.tokens.ValidateOIDCStateToken(stateToken)
+	if err != nil || claims.Provider != providerName || claims.ID != state {
+		return nil, errors.New("invalid or expired login state")
+	}
+
+	tokens, err := provider.Exchange(ctx, code, claims.CodeVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := provider.Identity(ctx, tokens, claims.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.resolveUser(ctx, providerName, info)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.userService.LoginExternal(ctx, user, client)
+}
+
+func (s *OIDCService) resolveUser(ctx context.Context, providerName string, info *auth.OIDCUserInfo) (*entities.User, error) {
+	identity, err := s.identityRepo.GetByProviderSubject(ctx, providerName, info.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if identity != nil {
+		user, err := s.userRepo.GetByID(ctx, identity.UserID)
+		if err != nil {
+			return nil, errors.New("user not found")
+		}
+		return user, nil
+	}
+
+	if info.Email == "" {
+		return nil, errors.New("identity provider did not return an email address")
+	}
+
+	// Só liga a uma conta existente quando o provedor garante que o e-mail
+	// pertence a quem está logando
+	exists, err := s.userRepo.CheckEmailExists(ctx, info.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	var user *entities.User
+	if exists {
+		if !info.EmailVerified {
+			return nil, errors.New("an account with this email already exists; sign in with your password to continue")
+		}
+		user, err = s.userRepo.GetByEmail(ctx, info.Email)
+		if err != nil {
+			return nil, err
+		}
+		// A conta também precisa ter confirmado o e-mail: qualquer um pode se
+		// cadastrar com o e-mail de outra pessoa, e ligar a identidade a essa
+		// conta deixaria a senha e as sessões de quem a criou valendo
+		if !user.EmailVerified {
+			return nil, errors.New("an account with this email already exists; sign in with your password to continue")
+		}
+	} else {
+		user, err = s.createUser(ctx, info)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.identityRepo.Create(ctx, &entities.UserIdentity{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		Provider:  providerName,
+		Subject:   info.Subject,
+		Email:     info.Email,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// createUser cria uma conta sem senha; o usuário pode definir uma depois
+// pelo fluxo de recuperação de senha.
+func (s *OIDCService) createUser(ctx context.Context, info *auth.OIDCUserInfo) (*entities.User, error) {
+	username, err := s.availableUsername(ctx, info)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	user := &entities.User{
+		ID:            uuid.New(),
+		Username:      username,
+		Email:         info.Email,
+		FullName:      info.Name,
+		Role:          entities.RoleUser,
+		IsActive:      true,
+		EmailVerified: info.EmailVerified,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (s *OIDCService) availableUsername(ctx context.Context, info *auth.OIDCUserInfo) (string, error) {
+	base := info.PreferredUsername
+	if base == "" {
+		base = strings.SplitN(info.Email, "@", 2)[0]
+	}
+	base = usernameInvalidChars.ReplaceAllString(strings.ToLower(base), "_")
+	if len(base) < 3 || reservedUsernames[base] {
+		base = "user_" + base
+	}
+	// Deixa espaço para o sufixo numérico sem passar dos 20 caracteres
+	if len(base) > 18 {
+		base = base[:18]
+	}
+
+	candidate := base
+	for i := 2; i < 100; i++ {
+		free, err := s.usernameFree(ctx, candidate)
+		if err != nil {
+			return "", err
+		}
+		if free {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s%d", base, i)
+	}
+
+	for i := 0; i < 5; i++ {
+		candidate = "user_" + uuid.NewString()[:8]
+		free, err := s.usernameFree(ctx, candidate)
+		if err != nil {
+			return "", err
+		}
+		if free {
+			return candidate, nil
+		}
+	}
+
+	return "", errors.New("could not find an available username")
+}
+
+// usernameFree aplica ao nome gerado as mesmas regras do cadastro, inclusive
+// a reserva dos nomes trocados há pouco.
+func (s *OIDCService) usernameFree(ctx context.Context, username string) (bool, error) {
+	if err := validateUsername(username); err != nil {
+		return false, nil
+	}
+
+	err := usernameAvailable(ctx, s.userRepo, s.historyRepo, username, uuid.Nil)
+	if errors.Is(err, repositories.ErrUsernameTaken) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,439 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/repositories"
+	"github.com/elaurentium/exilium-blog-backend/internal/infra/auth"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+)
+
+const (
+	mockClientID = "blog-client"
+	mockCode     = "auth-code"
+	mockSubject  = "provider-user-1"
+)
+
+// mockOIDCProvider é um provedor OIDC local: discovery, JWKS, token e
+// userinfo. Os campos controlam o ID token emitido pelo endpoint de token.
+type mockOIDCProvider struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	signer          *rsa.PrivateKey
+	issuer          string
+	audience        string
+	nonce           string
+	expiresIn       time.Duration
+	userInfoSubject string
+	emailVerified   bool
+
+	// challenge é o code_challenge da URL de autorização e state o valor
+	// devolvido ao callback
+	challenge string
+	state     string
+}
+
+func newMockOIDCProvider(t *testing.T) *mockOIDCProvider {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := &mockOIDCProvider{
+		key:             key,
+		signer:          key,
+		audience:        mockClientID,
+		expiresIn:       5 * time.Minute,
+		userInfoSubject: mockSubject,
+		emailVerified:   true,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]string{
+			"issuer":                 m.server.URL,
+			"authorization_endpoint": m.server.URL + "/authorize",
+			"token_endpoint":         m.server.URL + "/token",
+			"userinfo_endpoint":      m.server.URL + "/userinfo",
+			"jwks_uri":               m.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, auth.JWKSet{Keys: []auth.JWK{{
+			Kty: "RSA",
+			Kid: "k1",
+			Use: "sig",
+			Alg: "RS256",
+			N:   base64.RawURLEncoding.EncodeToString(m.key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(m.key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", m.token)
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		writeJSON(w, map[string]interface{}{
+			"sub":            m.userInfoSubject,
+			"email":          "user@example.com",
+			"email_verified": m.emailVerified,
+		})
+	})
+
+	m.server = httptest.NewServer(mux)
+	t.Cleanup(m.server.Close)
+	m.issuer = m.server.URL
+	return m
+}
+
+func (m *mockOIDCProvider) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if r.PostForm.Get("code") != mockCode || base64.RawURLEncoding.EncodeToString(sum[:]) != m.challenge {
+		w.WriteHeader(http.StatusBadRequest)
+		writeJSON(w, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   m.issuer,
+		"aud":   m.audience,
+		"sub":   mockSubject,
+		"nonce": m.nonce,
+		"iat":   now.Unix(),
+		"exp":   now.Add(m.expiresIn).Unix(),
+	})
+	idToken.Header["kid"] = "k1"
+	signed, err := idToken.SignedString(m.signer)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, map[string]string{"access_token": "access-token", "id_token": signed})
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+type fakeIdentityRepo struct {
+	identities []*entities.UserIdentity
+}
+
+func (r *fakeIdentityRepo) Create(ctx context.Context, identity *entities.UserIdentity) error {
+	r.identities = append(r.identities, identity)
+	return nil
+}
+
+func (r *fakeIdentityRepo) GetByProviderSubject(ctx context.Context, provider, subject string) (*entities.UserIdentity, error) {
+	for _, identity := range r.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			return identity, nil
+		}
+	}
+	return nil, nil
+}
+
+// fakeUserRepo só implementa o que os testes do pacote usam; como o
+// repositório real, devolve erro para usuários que não existem
+type fakeUserRepo struct {
+	repositories.UserRepository
+	users map[uuid.UUID]*entities.User
+}
+
+func (r *fakeUserRepo) GetByID(ctx context.Context, id uuid.UUID) (*entities.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, errors.New("user not found")
+	}
+	return user, nil
+}
+
+func (r *fakeUserRepo) GetByEmail(ctx context.Context, email string) (*entities.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, errors.New("user not found")
+}
+
+func (r *fakeUserRepo) CheckEmailExists(ctx context.Context, email string) (bool, error) {
+	_, err := r.GetByEmail(ctx, email)
+	return err == nil, nil
+}
+
+func newTestOIDCService(users *fakeUserRepo, identities *fakeIdentityRepo, mock *mockOIDCProvider) (*OIDCService, *auth.JWTService) {
+	tokens := auth.NewJWTService(auth.JWTConfig{
+		Issuer:          "test",
+		Audience:        "test",
+		SecretKey:       "test-secret",
+		MFAChallengeExp: time.Minute,
+	})
+	userService := NewUserService(users, nil, nil, nil, nil, tokens, nil, nil, nil, nil)
+	provider := auth.NewOIDCProvider(auth.OIDCProviderConfig{
+		Name:        "mock",
+		Issuer:      mock.server.URL,
+		ClientID:    mockClientID,
+		RedirectURL: "http://localhost/callback",
+		Scopes:      []string{"openid", "email"},
+	})
+	return NewOIDCService([]*auth.OIDCProvider{provider}, identities, users, nil, userService, tokens), tokens
+}
+
+// runOIDCLogin faz o Begin, repassa nonce e code_challenge ao provedor como
+// um navegador faria e chama o Callback. mutate roda entre os dois.
+func runOIDCLogin(ctx context.Context, t *testing.T, service *OIDCService, mock *mockOIDCProvider, mutate func(m *mockOIDCProvider)) (*LoginResult, error) {
+	t.Helper()
+
+	login, err := service.Begin(ctx, "mock")
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	authURL, err := url.Parse(login.AuthURL)
+	if err != nil {
+		t.Fatalf("invalid auth URL: %v", err)
+	}
+	query := authURL.Query()
+	if query.Get("nonce") == "" || query.Get("code_challenge") == "" {
+		t.Fatalf("auth URL without nonce or code_challenge: %s", login.AuthURL)
+	}
+	mock.nonce = query.Get("nonce")
+	mock.challenge = query.Get("code_challenge")
+	mock.state = query.Get("state")
+	if mutate != nil {
+		mutate(mock)
+	}
+
+	return service.Callback(ctx, "mock", mockCode, mock.state, login.StateToken, ClientInfo{})
+}
+
+func TestOIDCServiceBeginCallback(t *testing.T) {
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		mutate   func(m *mockOIDCProvider)
+		badState bool
+		wantErr  bool
+	}{
+		{name: "valid login"},
+		{name: "state mismatch", badState: true, wantErr: true},
+		{name: "nonce mismatch", mutate: func(m *mockOIDCProvider) { m.nonce = "other-nonce" }, wantErr: true},
+		{name: "bad signature", mutate: func(m *mockOIDCProvider) { m.signer = otherKey }, wantErr: true},
+		{name: "wrong audience", mutate: func(m *mockOIDCProvider) { m.audience = "other-client" }, wantErr: true},
+		{name: "wrong issuer", mutate: func(m *mockOIDCProvider) { m.issuer = "https://evil.example.com" }, wantErr: true},
+		{name: "expired id token", mutate: func(m *mockOIDCProvider) { m.expiresIn = -time.Minute }, wantErr: true},
+		{name: "userinfo subject mismatch", mutate: func(m *mockOIDCProvider) { m.userInfoSubject = "someone-else" }, wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			mock := newMockOIDCProvider(t)
+
+			// Usuário já ligado à identidade e com 2FA: o login termina no
+			// desafio MFA sem tocar em sessões nem refresh tokens
+			user := &entities.User{ID: uuid.New(), Username: "alice", IsActive: true, TOTPEnabled: true}
+			identities := &fakeIdentityRepo{identities: []*entities.UserIdentity{
+				{ID: uuid.New(), UserID: user.ID, Provider: "mock", Subject: mockSubject},
+			}}
+			users := &fakeUserRepo{users: map[uuid.UUID]*entities.User{user.ID: user}}
+
+			service, tokens := newTestOIDCService(users, identities, mock)
+
+			mutate := tc.mutate
+			if tc.badState {
+				mutate = func(m *mockOIDCProvider) { m.state = "forged-state" }
+			}
+			result, err := runOIDCLogin(ctx, t, service, mock, mutate)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Callback: %v", err)
+			}
+			if !result.MFARequired {
+				t.Fatal("expected an MFA challenge")
+			}
+			claims, err := tokens.ValidateMFAToken(result.MFAToken)
+			if err != nil || claims.UserID != user.ID {
+				t.Fatalf("MFA token not issued for the linked user: %v", err)
+			}
+		})
+	}
+}
+
+func TestOIDCServiceLinkByEmail(t *testing.T) {
+	tests := []struct {
+		name                  string
+		accountVerified       bool
+		providerEmailVerified bool
+		wantLinked            bool
+	}{
+		{name: "verified account and provider email", accountVerified: true, providerEmailVerified: true, wantLinked: true},
+		// Alguém se cadastrou antes com o e-mail da vítima: ligar a identidade
+		// deixaria a senha dessa pessoa valendo na conta
+		{name: "unverified account", accountVerified: false, providerEmailVerified: true},
+		{name: "unverified provider email", accountVerified: true, providerEmailVerified: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			mock := newMockOIDCProvider(t)
+			mock.emailVerified = tc.providerEmailVerified
+
+			user := &entities.User{
+				ID:             uuid.New(),
+				Username:       "alice",
+				Email:          "user@example.com",
+				HashedPassword: "existing-hash",
+				IsActive:       true,
+				EmailVerified:  tc.accountVerified,
+				TOTPEnabled:    true,
+			}
+			identities := &fakeIdentityRepo{}
+			users := &fakeUserRepo{users: map[uuid.UUID]*entities.User{user.ID: user}}
+			service, tokens := newTestOIDCService(users, identities, mock)
+
+			result, err := runOIDCLogin(ctx, t, service, mock, nil)
+			if !tc.wantLinked {
+				if err == nil {
+					t.Fatal("expected the login to be refused")
+				}
+				if len(identities.identities) != 0 {
+					t.Fatal("identity linked despite the error")
+				}
+				if user.EmailVerified != tc.accountVerified || user.HashedPassword != "existing-hash" {
+					t.Fatalf("existing account was modified: %+v", user)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Callback: %v", err)
+			}
+			if len(identities.identities) != 1 || identities.identities[0].UserID != user.ID {
+				t.Fatalf("identity not linked to the existing account: %+v", identities.identities)
+			}
+			claims, err := tokens.ValidateMFAToken(result.MFAToken)
+			if err != nil || claims.UserID != user.ID {
+				t.Fatalf("MFA token not issued for the linked user: %v", err)
+			}
+		})
+	}
+}
+
+func (r *fakeUserRepo) CheckUsernameExists(ctx context.Context, username string) (bool, error) {
+	for _, user := range r.users {
+		if strings.EqualFold(user.Username, username) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type fakeHistoryRepo struct {
+	repositories.UsernameHistoryRepository
+	changes []*entities.UsernameChange
+}
+
+func (r *fakeHistoryRepo) GetRecentByOldUsername(ctx context.Context, username string, since time.Time) (*entities.UsernameChange, error) {
+	for _, change := range r.changes {
+		if strings.EqualFold(change.OldUsername, username) && change.ChangedAt.After(since) {
+			return change, nil
+		}
+	}
+	return nil, nil
+}
+
+func TestOIDCServiceAvailableUsername(t *testing.T) {
+	taken := func(names ...string) map[uuid.UUID]*entities.User {
+		users := make(map[uuid.UUID]*entities.User, len(names))
+		for _, name := range names {
+			users[uuid.New()] = &entities.User{Username: name}
+		}
+		return users
+	}
+	manyTaken := []string{"alice"}
+	for i := 2; i < 100; i++ {
+		manyTaken = append(manyTaken, fmt.Sprintf("alice%d", i))
+	}
+
+	tests := []struct {
+		name     string
+		info     auth.OIDCUserInfo
+		users    map[uuid.UUID]*entities.User
+		renamed  []string
+		expected string
+		random   bool
+	}{
+		{name: "preferred username", info: auth.OIDCUserInfo{PreferredUsername: "Alice"}, expected: "alice"},
+		{name: "from the email", info: auth.OIDCUserInfo{Email: "bob.smith@example.com"}, expected: "bob_smith"},
+		{name: "taken with other case", info: auth.OIDCUserInfo{PreferredUsername: "alice"}, users: taken("ALICE"), expected: "alice2"},
+		{name: "held after a rename", info: auth.OIDCUserInfo{PreferredUsername: "alice"}, renamed: []string{"Alice"}, expected: "alice2"},
+		{name: "reserved", info: auth.OIDCUserInfo{PreferredUsername: "admin"}, expected: "user_admin"},
+		{name: "every suffix taken", info: auth.OIDCUserInfo{PreferredUsername: "alice"}, users: taken(manyTaken...), random: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			history := &fakeHistoryRepo{}
+			for _, name := range tc.renamed {
+				history.changes = append(history.changes, &entities.UsernameChange{
+					UserID:      uuid.New(),
+					OldUsername: name,
+					ChangedAt:   time.Now().Add(-time.Hour),
+				})
+			}
+			users := &fakeUserRepo{users: tc.users}
+			service := NewOIDCService(nil, nil, users, history, nil, nil)
+
+			got, err := service.availableUsername(context.Background(), &tc.info)
+			if err != nil {
+				t.Fatalf("availableUsername: %v", err)
+			}
+			if tc.random {
+				if !strings.HasPrefix(got, "user_") || validateUsername(got) != nil {
+					t.Fatalf("fallback username %q is not valid", got)
+				}
+				return
+			}
+			if got != tc.expected {
+				t.Fatalf("got %q, want %q", got, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/repositories"
+	"github.com/elaurentium/exilium-blog-backend/internal/infra/auth"
+	"github.com/elaurentium/exilium-blog-backend/internal/infra/mailer"
+	"github.com/elaurentium/exilium-blog-backend/pkg/logger"
+	"github.com/google/uuid"
+)
+
+const (
+	passwordResetTokenTTL = time.Hour
+	// Quantos pedidos de redefinição um mesmo e-mail pode fazer por janela
+	passwordResetLimit  = 3
+	passwordResetWindow = time.Hour
+)
+
+type PasswordResetService struct {
+	userService *UserService
+	userRepo    repositories.UserRepository
+	resetRepo   repositories.PasswordResetRepository
+	mailer      mailer.Mailer
+	resetURL    string
+	logger      *logger.Logger
+}
+
+func NewPasswordResetService(
+	userService *UserService,
+	userRepo repositories.UserRepository,
+	resetRepo repositories.PasswordResetRepository,
+	mailer mailer.Mailer,
+	resetURL string,
+) *PasswordResetService {
+	return &PasswordResetService{
+		userService: userService,
+		userRepo:    userRepo,
+		resetRepo:   resetRepo,
+		mailer:      mailer,
+		resetURL:    resetURL,
+		logger:      logger.NewLogger(),
+	}
+}
+
+// RequestReset não revela se o e-mail está cadastrado: para e-mails
+// desconhecidos ou acima do limite simplesmente não envia nada, e falhas ao
+// gerar ou enviar o link só vão para o log, com a mesma resposta de sempre.
+func (s *PasswordResetService) RequestReset(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil || !user.IsActive {
+		return nil
+	}
+
+	if err := s.sendResetLink(ctx, user); err != nil {
+		s.logger.Error(fmt.Sprintf("failed to send password reset to user %s: %v", user.ID, err))
+	}
+	return nil
+}
+
+func (s *PasswordResetService) sendResetLink(ctx context.Context, user *entities.User) error {
+	now := time.Now()
+	count, err := s.resetRepo.CountSince(ctx, user.ID, now.Add(-passwordResetWindow))
+	if err != nil {
+		return err
+	}
+	if count >= passwordResetLimit {
+		return nil
+	}
+
+	token, tokenHash, err := auth.GenerateOpaqueToken(32)
+	if err != nil {
+		return err
+	}
+
+	err = s.resetRepo.Create(ctx, &entities.PasswordResetToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(passwordResetTokenTTL),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return err
+	}
+
+	link := s.resetURL + "?token=" + url.QueryEscape(token)
+
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nSomeone asked to reset the password of your account. If it was you, open the link below within %s:\n\n%s\n\nIf it wasn't you, you can ignore this email.\n",
+			user.Username, passwordResetTokenTTL, link,
+		),
+	})
+}
+
+func (s *PasswordResetService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	reset, err := s.resetRepo.GetByHash(ctx, auth.HashToken(token))
+	if err != nil {
+		return err
+	}
+	if reset == nil || reset.UsedAt != nil || time.Now().After(reset.ExpiresAt) {
+		return errors.New("invalid or expired reset token")
+	}
+
+	// Consome o token antes de trocar a senha para que ele não possa ser
+	// usado duas vezes em requisições concorrentes
+	used, err := s.resetRepo.MarkUsed(ctx, reset.ID)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return errors.New("invalid or expired reset token")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, reset.UserID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	if err := s.userService.setPassword(ctx, user, newPassword); err != nil {
+		return err
+	}
+
+	return s.resetRepo.InvalidateAllForUser(ctx, user.ID)
+}
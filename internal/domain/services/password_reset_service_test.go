@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/repositories"
+	"github.com/elaurentium/exilium-blog-backend/internal/infra/mailer"
+	"github.com/google/uuid"
+)
+
+type fakeResetRepo struct {
+	repositories.PasswordResetRepository
+	created int
+}
+
+func (r *fakeResetRepo) CountSince(ctx context.Context, userID uuid.UUID, since time.Time) (int, error) {
+	return r.created, nil
+}
+
+func (r *fakeResetRepo) Create(ctx context.Context, token *entities.PasswordResetToken) error {
+	r.created++
+	return nil
+}
+
+type fakeMailer struct {
+	err  error
+	sent []mailer.Message
+}
+
+func (m *fakeMailer) Send(ctx context.Context, msg mailer.Message) error {
+	m.sent = append(m.sent, msg)
+	return m.err
+}
+
+func TestPasswordResetRequestDoesNotRevealAccounts(t *testing.T) {
+	user := &entities.User{ID: uuid.New(), Username: "alice", Email: "alice@example.com", IsActive: true}
+
+	tests := []struct {
+		name     string
+		email    string
+		mailErr  error
+		wantSent int
+	}{
+		{name: "unknown email", email: "nobody@example.com"},
+		{name: "known email", email: user.Email, wantSent: 1},
+		{name: "known email with failing mailer", email: user.Email, mailErr: errors.New("smtp down"), wantSent: 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			users := &fakeUserRepo{users: map[uuid.UUID]*entities.User{user.ID: user}}
+			mail := &fakeMailer{err: tc.mailErr}
+			service := NewPasswordResetService(nil, users, &fakeResetRepo{}, mail, "http://localhost/reset")
+
+			if err := service.RequestReset(context.Background(), tc.email); err != nil {
+				t.Fatalf("RequestReset returned %v", err)
+			}
+			if len(mail.sent) != tc.wantSent {
+				t.Fatalf("sent %d emails, want %d", len(mail.sent), tc.wantSent)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/repositories"
+	"github.com/google/uuid"
+)
+
+// userHasPermission consulta o papel atual no banco em vez de confiar no
+// papel gravado no token, que pode estar desatualizado.
+func userHasPermission(ctx context.Context, userRepo repositories.UserRepository, userID uuid.UUID, permission string) (bool, error) {
+	user, err := userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return false, errors.New("user not found")
+	}
+
+	return entities.HasPermission(user.Role, permission), nil
+}
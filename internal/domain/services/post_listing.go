@@ -0,0 +1,39 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+)
+
+// ParsePostListing valida ?sort= e ?t= das listagens. Sem sort, a ordenação
+// é hot; top e controversial sem janela olham o último dia.
+func ParsePostListing(sort, window string) (entities.PostListing, error) {
+	sort = strings.ToLower(strings.TrimSpace(sort))
+	window = strings.ToLower(strings.TrimSpace(window))
+
+	switch sort {
+	case "":
+		sort = entities.PostSortHot
+	case entities.PostSortHot, entities.PostSortNew, entities.PostSortRising,
+		entities.PostSortTop, entities.PostSortControversial:
+	default:
+		return entities.PostListing{}, errors.New("sort must be one of hot, new, top, rising or controversial")
+	}
+
+	if sort != entities.PostSortTop && sort != entities.PostSortControversial {
+		return entities.PostListing{Sort: sort}, nil
+	}
+
+	switch window {
+	case "":
+		window = entities.TimeWindowDay
+	case entities.TimeWindowHour, entities.TimeWindowDay, entities.TimeWindowWeek,
+		entities.TimeWindowMonth, entities.TimeWindowYear, entities.TimeWindowAll:
+	default:
+		return entities.PostListing{}, errors.New("t must be one of hour, day, week, month, year or all")
+	}
+
+	return entities.PostListing{Sort: sort, Window: window}, nil
+}
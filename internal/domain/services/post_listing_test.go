@@ -0,0 +1,37 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+)
+
+func TestParsePostListing(t *testing.T) {
+	tests := []struct {
+		sort, window string
+		expected     entities.PostListing
+		wantErr      bool
+	}{
+		{expected: entities.PostListing{Sort: entities.PostSortHot}},
+		{sort: "new", window: "week", expected: entities.PostListing{Sort: entities.PostSortNew}},
+		{sort: "Rising", expected: entities.PostListing{Sort: entities.PostSortRising}},
+		{sort: "top", expected: entities.PostListing{Sort: entities.PostSortTop, Window: entities.TimeWindowDay}},
+		{sort: "top", window: " ALL ", expected: entities.PostListing{Sort: entities.PostSortTop, Window: entities.TimeWindowAll}},
+		{sort: "controversial", window: "hour", expected: entities.PostListing{Sort: entities.PostSortControversial, Window: entities.TimeWindowHour}},
+		{sort: "best", wantErr: true},
+		{sort: "top", window: "decade", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		got, err := ParsePostListing(tc.sort, tc.window)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("ParsePostListing(%q, %q) = %+v, want an error", tc.sort, tc.window, got)
+			}
+			continue
+		}
+		if err != nil || got != tc.expected {
+			t.Errorf("ParsePostListing(%q, %q) = %+v, %v; want %+v", tc.sort, tc.window, got, err, tc.expected)
+		}
+	}
+}
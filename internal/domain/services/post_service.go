@@ -3,6 +3,8 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
@@ -14,17 +16,23 @@ type PostService struct {
 	postRepo      repositories.PostRepository
 	userRepo      repositories.UserRepository
 	subRepo repositories.SubRepository
+	voteRepo repositories.VoteRepository
+	requireVerifiedEmail bool
 }
 
 func NewPostService(
 	postRepo repositories.PostRepository,
 	userRepo repositories.UserRepository,
 	subRepo repositories.SubRepository,
+	voteRepo repositories.VoteRepository,
+	requireVerifiedEmail bool,
 ) *PostService {
 	return &PostService{
 		postRepo:      postRepo,
 		userRepo:      userRepo,
 		subRepo: subRepo,
+		voteRepo: voteRepo,
+		requireVerifiedEmail: requireVerifiedEmail,
 	}
 }
 
@@ -37,16 +45,32 @@ func (s *PostService) CreatePost(
 ) (*entities.Post, error) {
 	// Verificar se o subreddit existe
 	subreddit, err := s.subRepo.GetByID(ctx, subID)
-	if err != nil {
+	if err != nil || subreddit == nil {
 		return nil, errors.New("subreddit not found")
 	}
 
 	// Verificar se o usuário existe
-	_, err = s.userRepo.GetByID(ctx, userID)
+	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		return nil, errors.New("user not found")
 	}
 
+	if s.requireVerifiedEmail && !user.EmailVerified {
+		return nil, errors.New("email must be verified before posting")
+	}
+
+	banned, err := s.subRepo.IsBanned(ctx, subID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if banned {
+		return nil, errors.New("you are banned from this sub")
+	}
+
+	if user.Karma() < subreddit.MinKarma {
+		return nil, fmt.Errorf("you need at least %d karma to post in this sub", subreddit.MinKarma)
+	}
+
 	// Verificar se o subreddit é privado
 	if subreddit.IsPrivate {
 		// Aqui poderia ter uma lógica para verificar se o usuário é membro do subreddit
@@ -76,8 +100,19 @@ func (s *PostService) CreatePost(
 	return post, nil
 }
 
-func (s *PostService) GetPost(ctx context.Context, id uuid.UUID) (*entities.Post, error) {
-	return s.postRepo.GetByID(ctx, id)
+// As leituras trazem o voto do leitor em ViewerVote; visitantes anônimos
+// passam uuid.Nil.
+func (s *PostService) GetPost(ctx context.Context, id, viewerID uuid.UUID) (*entities.Post, error) {
+	post, err := s.postRepo.GetByID(ctx, id)
+	if err != nil || post == nil {
+		return nil, errors.New("post not found")
+	}
+
+	if err := attachPostVotes(ctx, s.voteRepo, viewerID, post); err != nil {
+		return nil, err
+	}
+
+	return post, nil
 }
 
 func (s *PostService) UpdatePost(
@@ -88,7 +123,7 @@ func (s *PostService) UpdatePost(
 	content string,
 ) (*entities.Post, error) {
 	post, err := s.postRepo.GetByID(ctx, id)
-	if err != nil {
+	if err != nil || post == nil {
 		return nil, errors.New("post not found")
 	}
 
@@ -116,37 +151,108 @@ func (s *PostService) UpdatePost(
 
 func (s *PostService) DeletePost(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
 	post, err := s.postRepo.GetByID(ctx, id)
-	if err != nil {
+	if err != nil || post == nil {
 		return errors.New("post not found")
 	}
 
-	// Verificar se o usuário é o proprietário do post
+	// O autor pode apagar o próprio post; moderadores, qualquer um
 	if post.UserID != userID {
-		// Alternativamente, poderia verificar se o usuário é moderador ou admin
-		return errors.New("user not authorized to delete this post")
+		allowed, err := userHasPermission(ctx, s.userRepo, userID, entities.PermissionPostDeleteAny)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return errors.New("user not authorized to delete this post")
+		}
 	}
 
 	return s.postRepo.Delete(ctx, id)
 }
 
-func (s *PostService) UpvotePost(ctx context.Context, postID uuid.UUID, userID uuid.UUID) error {
-	return s.postRepo.UpvotePost(ctx, postID, userID)
+// VotePost registra o voto do usuário: 1 (upvote), -1 (downvote) ou 0 para
+// remover. Repetir o mesmo voto não muda nada. Retorna o post com os
+// contadores atualizados.
+func (s *PostService) VotePost(ctx context.Context, postID uuid.UUID, userID uuid.UUID, dir int) (*entities.Post, error) {
+	if dir < -1 || dir > 1 {
+		return nil, errors.New("vote direction must be 1, 0 or -1")
+	}
+
+	found, err := s.voteRepo.SetPostVote(ctx, postID, userID, dir)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, errors.New("post not found")
+	}
+
+	return s.GetPost(ctx, postID, userID)
 }
 
-func (s *PostService) DownvotePost(ctx context.Context, postID uuid.UUID, userID uuid.UUID) error {
-	return s.postRepo.DownvotePost(ctx, postID, userID)
+// SetSuggestedSort define a ordenação padrão dos comentários do post. Só o
+// autor e os moderadores do sub podem mudá-la; sort vazio remove a sugestão.
+func (s *PostService) SetSuggestedSort(ctx context.Context, postID uuid.UUID, userID uuid.UUID, sort string) (*entities.Post, error) {
+	sort, err := ParseCommentSort(sort)
+	if err != nil {
+		return nil, err
+	}
+
+	post, err := s.postRepo.GetByID(ctx, postID)
+	if err != nil || post == nil {
+		return nil, errors.New("post not found")
+	}
+
+	if post.UserID != userID {
+		isModerator, err := s.subRepo.IsModerator(ctx, post.SubID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !isModerator {
+			return nil, errors.New("user not authorized to change this post's suggested sort")
+		}
+	}
+
+	var suggested *string
+	if sort != "" {
+		suggested = &sort
+	}
+
+	if err := s.postRepo.SetSuggestedSort(ctx, postID, suggested); err != nil {
+		return nil, err
+	}
+
+	return s.GetPost(ctx, postID, userID)
 }
 
-func (s *PostService) RemoveVote(ctx context.Context, postID uuid.UUID, userID uuid.UUID) error {
-	return s.postRepo.RemoveVote(ctx, postID, userID)
+// As listagens escondem os autores que o leitor bloqueou ou silenciou.
+func (s *PostService) GetFrontPage(ctx context.Context, viewerID uuid.UUID, listing entities.PostListing, limit, offset int) ([]*entities.Post, error) {
+	posts, err := s.postRepo.GetFrontPage(ctx, viewerID, listing, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return s.withViewerVotes(ctx, viewerID, posts)
 }
 
-func (s *PostService) GetTrendingPosts(ctx context.Context, limit int) ([]*entities.Post, error) {
-	return s.postRepo.GetTrending(ctx, limit)
+func (s *PostService) GetPostsBySub(ctx context.Context, subID, viewerID uuid.UUID, listing entities.PostListing, limit, offset int) ([]*entities.Post, error) {
+	posts, err := s.postRepo.GetBySub(ctx, subID, viewerID, listing, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return s.withViewerVotes(ctx, viewerID, posts)
+}
+
+func (s *PostService) GetPostsBySubName(ctx context.Context, name string, viewerID uuid.UUID, listing entities.PostListing, limit, offset int) ([]*entities.Post, error) {
+	sub, err := s.subRepo.GetByName(ctx, strings.ToLower(strings.TrimSpace(name)))
+	if err != nil || sub == nil {
+		return nil, errors.New("sub not found")
+	}
+	return s.GetPostsBySub(ctx, sub.ID, viewerID, listing, limit, offset)
 }
 
-func (s *PostService) GetPostsBySub(ctx context.Context, subID uuid.UUID, limit, offset int) ([]*entities.Post, error) {
-	return s.postRepo.GetBySub(ctx, subID, limit, offset)
+func (s *PostService) withViewerVotes(ctx context.Context, viewerID uuid.UUID, posts []*entities.Post) ([]*entities.Post, error) {
+	if err := attachPostVotes(ctx, s.voteRepo, viewerID, posts...); err != nil {
+		return nil, err
+	}
+	return posts, nil
 }
 
 func (s *PostService) GetPostsByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.Post, error) {
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/repositories"
+)
+
+// ProfileService expõe os perfis públicos e a atividade de cada usuário.
+type ProfileService struct {
+	userRepo    repositories.UserRepository
+	postRepo    repositories.PostRepository
+	commentRepo repositories.CommentRepository
+	followRepo  repositories.FollowRepository
+	historyRepo repositories.UsernameHistoryRepository
+}
+
+func NewProfileService(
+	userRepo repositories.UserRepository,
+	postRepo repositories.PostRepository,
+	commentRepo repositories.CommentRepository,
+	followRepo repositories.FollowRepository,
+	historyRepo repositories.UsernameHistoryRepository,
+) *ProfileService {
+	return &ProfileService{
+		userRepo:    userRepo,
+		postRepo:    postRepo,
+		commentRepo: commentRepo,
+		followRepo:  followRepo,
+		historyRepo: historyRepo,
+	}
+}
+
+func (s *ProfileService) GetProfile(ctx context.Context, username string) (*entities.PublicUser, error) {
+	user, err := s.findUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := user.Public()
+	profile.Followers, profile.Following, err = s.followRepo.Counts(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+func (s *ProfileService) GetPosts(ctx context.Context, username string, limit, offset int) ([]*entities.Post, error) {
+	user, err := s.findUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	return s.postRepo.GetByUser(ctx, user.ID, limit, offset)
+}
+
+func (s *ProfileService) GetComments(ctx context.Context, username string, limit, offset int) ([]*entities.Comment, error) {
+	user, err := s.findUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	return s.commentRepo.GetByUser(ctx, user.ID, limit, offset)
+}
+
+// findUser trata contas apagadas e desativadas como inexistentes. Nomes
+// trocados há menos de usernameCooldown retornam UsernameMovedError.
+func (s *ProfileService) findUser(ctx context.Context, username string) (*entities.User, error) {
+	user, err := s.userRepo.GetByUsername(ctx, username)
+	if err == nil && user != nil && user.IsActive {
+		return user, nil
+	}
+
+	change, err := s.historyRepo.GetRecentByOldUsername(ctx, username, time.Now().Add(-usernameCooldown))
+	if err == nil && change != nil {
+		current, err := s.userRepo.GetByID(ctx, change.UserID)
+		if err == nil && current != nil && current.IsActive {
+			return nil, &UsernameMovedError{Username: current.Username}
+		}
+	}
+
+	return nil, errors.New("user not found")
+}
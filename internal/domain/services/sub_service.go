@@ -33,6 +33,7 @@ func (s *SubService) CreateSub(
 	rules []string,
 	creatorID uuid.UUID,
 	isPrivate bool,
+	minKarma int,
 ) (*entities.Sub, error) {
 	// Verificar se o nome do sub é válido
 	name = strings.ToLower(strings.TrimSpace(name))
@@ -44,6 +45,10 @@ func (s *SubService) CreateSub(
 		return nil, errors.New("sub name must be between 3 and 21 characters")
 	}
 
+	if minKarma < 0 {
+		return nil, errors.New("min karma cannot be negative")
+	}
+
 	// Verificar se o nome do sub já existe
 	existingSub, err := s.subRepo.GetByName(ctx, name)
 	if err == nil && existingSub != nil {
@@ -64,6 +69,7 @@ func (s *SubService) CreateSub(
 		Rules:       rules,
 		CreatorID:   creatorID,
 		IsPrivate:   isPrivate,
+		MinKarma:    minKarma,
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}
@@ -81,7 +87,11 @@ func (s *SubService) GetSub(ctx context.Context, id uuid.UUID) (*entities.Sub, e
 }
 
 func (s *SubService) GetSubByName(ctx context.Context, name string) (*entities.Sub, error) {
-	return s.subRepo.GetByName(ctx, strings.ToLower(strings.TrimSpace(name)))
+	sub, err := s.subRepo.GetByName(ctx, strings.ToLower(strings.TrimSpace(name)))
+	if err != nil || sub == nil {
+		return nil, errors.New("sub not found")
+	}
+	return sub, nil
 }
 
 func (s *SubService) UpdateSub(
@@ -93,15 +103,26 @@ func (s *SubService) UpdateSub(
 	isPrivate bool,
 	bannerURL string,
 	iconURL string,
+	minKarma int,
 ) (*entities.Sub, error) {
 	sub, err := s.subRepo.GetByID(ctx, id)
-	if err != nil {
+	if err != nil || sub == nil {
 		return nil, errors.New("sub not found")
 	}
 
-	// Verificar se o usuário é o criador do sub
+	if minKarma < 0 {
+		return nil, errors.New("min karma cannot be negative")
+	}
+
+	// Verificar se o usuário é o criador do sub ou um administrador
 	if sub.CreatorID != creatorID {
-		return nil, errors.New("user not authorized to update this sub")
+		allowed, err := userHasPermission(ctx, s.userRepo, creatorID, entities.PermissionSubUpdateAny)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, errors.New("user not authorized to update this sub")
+		}
 	}
 
 	sub.Description = description
@@ -109,6 +130,7 @@ func (s *SubService) UpdateSub(
 	sub.IsPrivate = isPrivate
 	sub.BannerURL = bannerURL
 	sub.IconURL = iconURL
+	sub.MinKarma = minKarma
 	sub.UpdatedAt = time.Now()
 
 	err = s.subRepo.Update(ctx, sub)
@@ -129,14 +151,93 @@ func (s *SubService) GetTrendingSub(ctx context.Context, limit int) ([]*entities
 
 func (s *SubService) DeleteSub(ctx context.Context, id uuid.UUID, creatorID uuid.UUID) error {
 	sub, err := s.subRepo.GetByID(ctx, id)
-	if err != nil {
+	if err != nil || sub == nil {
 		return errors.New("sub not found")
 	}
 
-	// Verificar se o usuário é o criador do sub
+	// Verificar se o usuário é o criador do sub ou um administrador
 	if sub.CreatorID != creatorID {
-		return errors.New("user not authorized to delete this sub")
+		allowed, err := userHasPermission(ctx, s.userRepo, creatorID, entities.PermissionSubDeleteAny)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return errors.New("user not authorized to delete this sub")
+		}
 	}
 
 	return s.subRepo.Delete(ctx, id)
-}
\ No newline at end of file
+}
+
+// BanUser impede o usuário de postar e comentar no sub. Podem banir os
+// moderadores do sub e quem tem a permissão sub.ban no site.
+func (s *SubService) BanUser(ctx context.Context, subID, actorID, userID uuid.UUID, reason string) (*entities.SubBan, error) {
+	sub, err := s.authorizeBan(ctx, subID, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if userID == actorID {
+		return nil, errors.New("you cannot ban yourself")
+	}
+	if userID == sub.CreatorID {
+		return nil, errors.New("the sub creator cannot be banned")
+	}
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	ban := &entities.SubBan{
+		SubID:     sub.ID,
+		UserID:    userID,
+		BannedBy:  actorID,
+		Reason:    strings.TrimSpace(reason),
+		CreatedAt: time.Now(),
+	}
+	if err := s.subRepo.Ban(ctx, ban); err != nil {
+		return nil, err
+	}
+
+	return ban, nil
+}
+
+func (s *SubService) UnbanUser(ctx context.Context, subID, actorID, userID uuid.UUID) error {
+	if _, err := s.authorizeBan(ctx, subID, actorID); err != nil {
+		return err
+	}
+
+	unbanned, err := s.subRepo.Unban(ctx, subID, userID)
+	if err != nil {
+		return err
+	}
+	if !unbanned {
+		return errors.New("user is not banned from this sub")
+	}
+
+	return nil
+}
+
+func (s *SubService) authorizeBan(ctx context.Context, subID, actorID uuid.UUID) (*entities.Sub, error) {
+	sub, err := s.subRepo.GetByID(ctx, subID)
+	if err != nil || sub == nil {
+		return nil, errors.New("sub not found")
+	}
+
+	isModerator, err := s.subRepo.IsModerator(ctx, sub.ID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if isModerator {
+		return sub, nil
+	}
+
+	allowed, err := userHasPermission(ctx, s.userRepo, actorID, entities.PermissionSubBan)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, errors.New("user not authorized to ban users from this sub")
+	}
+
+	return sub, nil
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/repositories"
+	"github.com/google/uuid"
+)
+
+type fakeSubRepo struct {
+	repositories.SubRepository
+	sub        *entities.Sub
+	moderators map[uuid.UUID]bool
+	bans       map[uuid.UUID]*entities.SubBan
+}
+
+func (r *fakeSubRepo) GetByID(ctx context.Context, id uuid.UUID) (*entities.Sub, error) {
+	if id != r.sub.ID {
+		return nil, nil
+	}
+	return r.sub, nil
+}
+
+func (r *fakeSubRepo) IsModerator(ctx context.Context, subID, userID uuid.UUID) (bool, error) {
+	return userID == r.sub.CreatorID || r.moderators[userID], nil
+}
+
+func (r *fakeSubRepo) Ban(ctx context.Context, ban *entities.SubBan) error {
+	r.bans[ban.UserID] = ban
+	return nil
+}
+
+func TestSubServiceBanUser(t *testing.T) {
+	creator := &entities.User{ID: uuid.New(), Role: entities.RoleUser}
+	subModerator := &entities.User{ID: uuid.New(), Role: entities.RoleUser}
+	siteModerator := &entities.User{ID: uuid.New(), Role: entities.RoleModerator}
+	admin := &entities.User{ID: uuid.New(), Role: entities.RoleAdmin}
+	member := &entities.User{ID: uuid.New(), Role: entities.RoleUser}
+	target := &entities.User{ID: uuid.New(), Role: entities.RoleUser}
+
+	tests := []struct {
+		name    string
+		actor   uuid.UUID
+		target  uuid.UUID
+		wantErr bool
+	}{
+		{name: "sub creator", actor: creator.ID, target: target.ID},
+		{name: "sub moderator", actor: subModerator.ID, target: target.ID},
+		{name: "site moderator with sub.ban", actor: siteModerator.ID, target: target.ID},
+		{name: "admin inherits sub.ban", actor: admin.ID, target: target.ID},
+		{name: "regular member", actor: member.ID, target: target.ID, wantErr: true},
+		{name: "ban yourself", actor: siteModerator.ID, target: siteModerator.ID, wantErr: true},
+		{name: "ban the creator", actor: siteModerator.ID, target: creator.ID, wantErr: true},
+		{name: "unknown user", actor: creator.ID, target: uuid.New(), wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			users := &fakeUserRepo{users: map[uuid.UUID]*entities.User{}}
+			for _, user := range []*entities.User{creator, subModerator, siteModerator, admin, member, target} {
+				users.users[user.ID] = user
+			}
+			subs := &fakeSubRepo{
+				sub:        &entities.Sub{ID: uuid.New(), CreatorID: creator.ID},
+				moderators: map[uuid.UUID]bool{subModerator.ID: true},
+				bans:       map[uuid.UUID]*entities.SubBan{},
+			}
+			service := NewSubService(subs, users)
+
+			ban, err := service.BanUser(context.Background(), subs.sub.ID, tc.actor, tc.target, " spam ")
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				if len(subs.bans) != 0 {
+					t.Fatal("ban recorded despite the error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BanUser: %v", err)
+			}
+			if subs.bans[tc.target] != ban || ban.BannedBy != tc.actor || ban.Reason != "spam" {
+				t.Fatalf("unexpected ban: %+v", ban)
+			}
+		})
+	}
+}
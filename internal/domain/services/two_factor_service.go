@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/repositories"
+	"github.com/elaurentium/exilium-blog-backend/internal/infra/auth"
+	"github.com/google/uuid"
+)
+
+const recoveryCodeCount = 10
+
+type TwoFactorEnrollment struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+type TwoFactorService struct {
+	userRepo     repositories.UserRepository
+	recoveryRepo repositories.RecoveryCodeRepository
+	hasher       auth.PasswordHasher
+	issuer       string
+}
+
+func NewTwoFactorService(
+	userRepo repositories.UserRepository,
+	recoveryRepo repositories.RecoveryCodeRepository,
+	hasher auth.PasswordHasher,
+	issuer string,
+) *TwoFactorService {
+	return &TwoFactorService{
+		userRepo:     userRepo,
+		recoveryRepo: recoveryRepo,
+		hasher:       hasher,
+		issuer:       issuer,
+	}
+}
+
+// Enroll gera um novo segredo, que só passa a valer depois de Confirm.
+func (s *TwoFactorService) Enroll(ctx context.Context, userID uuid.UUID) (*TwoFactorEnrollment, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if user.TOTPEnabled {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	user.TOTPSecret = secret
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorEnrollment{
+		Secret:     secret,
+		OTPAuthURI: auth.TOTPURI(s.issuer, user.Email, secret),
+	}, nil
+}
+
+// Confirm ativa o 2FA depois que o usuário prova que configurou o app,
+// devolvendo os códigos de recuperação (mostrados uma única vez).
+func (s *TwoFactorService) Confirm(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if user.TOTPEnabled {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+	if user.TOTPSecret == "" {
+		return nil, errors.New("two-factor enrollment not started")
+	}
+	ok, err := s.checkTOTP(ctx, user, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("invalid two-factor code")
+	}
+
+	codes, err := s.replaceRecoveryCodes(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	user.TOTPEnabled = true
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+func (s *TwoFactorService) Disable(ctx context.Context, userID uuid.UUID, password, code string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	if !user.TOTPEnabled {
+		return errors.New("two-factor authentication is not enabled")
+	}
+	if !s.hasher.VerifyPassword(password, user.HashedPassword, user.Salt) {
+		return errors.New("password is incorrect")
+	}
+
+	ok, err := s.VerifyCode(ctx, user, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("invalid two-factor code")
+	}
+
+	user.TOTPEnabled = false
+	user.TOTPSecret = ""
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	return s.recoveryRepo.DeleteForUser(ctx, user.ID)
+}
+
+func (s *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if !user.TOTPEnabled {
+		return nil, errors.New("two-factor authentication is not enabled")
+	}
+
+	// Aqui só vale o código do app: um código de recuperação não pode ser
+	// usado para gerar outros
+	ok, err := s.checkTOTP(ctx, user, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("invalid two-factor code")
+	}
+
+	return s.replaceRecoveryCodes(ctx, user.ID)
+}
+
+// VerifyCode aceita tanto o código TOTP quanto um código de recuperação,
+// que é consumido.
+func (s *TwoFactorService) VerifyCode(ctx context.Context, user *entities.User, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+	if _, ok := auth.ValidateTOTP(user.TOTPSecret, code, time.Now()); ok {
+		return s.checkTOTP(ctx, user, code)
+	}
+
+	return s.recoveryRepo.Use(ctx, user.ID, auth.HashToken(strings.ToLower(code)))
+}
+
+// checkTOTP aceita cada passo do TOTP uma única vez: um código já usado é
+// recusado mesmo dentro da janela de tolerância.
+func (s *TwoFactorService) checkTOTP(ctx context.Context, user *entities.User, code string) (bool, error) {
+	step, ok := auth.ValidateTOTP(user.TOTPSecret, code, time.Now())
+	if !ok {
+		return false, nil
+	}
+	return s.userRepo.UseTOTPStep(ctx, user.ID, step)
+}
+
+func (s *TwoFactorService) replaceRecoveryCodes(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	codes, err := auth.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i] = auth.HashToken(code)
+	}
+
+	if err := s.recoveryRepo.ReplaceForUser(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
@@ -11,18 +12,62 @@ import (
 	"github.com/google/uuid"
 )
 type UserService struct {
-	userRepo repositories.UserRepository
-	auth     auth.AuthService
+	userRepo         repositories.UserRepository
+	refreshTokenRepo repositories.RefreshTokenRepository
+	sessionRepo      repositories.SessionRepository
+	historyRepo      repositories.UsernameHistoryRepository
+	hasher           auth.PasswordHasher
+	tokens           *auth.JWTService
+	denylist         auth.TokenDenylist
+	verification     *EmailVerificationService
+	twoFactor        *TwoFactorService
+	loginGuard       *LoginGuard
 }
 
-func NewUserService(userRepo repositories.UserRepository, auth auth.AuthService) *UserService {
+// LoginResult traz os tokens da sessão ou, quando o usuário tem 2FA ativo,
+// apenas o desafio que deve ser respondido em VerifyMFA.
+type LoginResult struct {
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	MFARequired  bool   `json:"mfa_required,omitempty"`
+	MFAToken     string `json:"mfa_token,omitempty"`
+}
+
+func NewUserService(
+	userRepo repositories.UserRepository,
+	refreshTokenRepo repositories.RefreshTokenRepository,
+	sessionRepo repositories.SessionRepository,
+	historyRepo repositories.UsernameHistoryRepository,
+	hasher auth.PasswordHasher,
+	tokens *auth.JWTService,
+	denylist auth.TokenDenylist,
+	verification *EmailVerificationService,
+	twoFactor *TwoFactorService,
+	loginGuard *LoginGuard,
+) *UserService {
 	return &UserService{
-		userRepo: userRepo,
-		auth:     auth,
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		sessionRepo:      sessionRepo,
+		historyRepo:      historyRepo,
+		hasher:           hasher,
+		tokens:           tokens,
+		denylist:         denylist,
+		verification:     verification,
+		twoFactor:        twoFactor,
+		loginGuard:       loginGuard,
 	}
 }
 
 func (s *UserService) Register(ctx context.Context, username, email, password, fullName string, birthday string) (*entities.User, error) {
+	if err := validateUsername(username); err != nil {
+		return nil, err
+	}
+
+	if err := usernameAvailable(ctx, s.userRepo, s.historyRepo, username, uuid.Nil); err != nil {
+		return nil, err
+	}
+
 	emailExists, err := s.userRepo.CheckEmailExists(ctx, email)
 
 	if err != nil {
@@ -32,7 +77,7 @@ func (s *UserService) Register(ctx context.Context, username, email, password, f
 		return nil, errors.New("email already exists")
 	}
 
-	hashedPassword, salt, err := s.auth.HashPassword(password)
+	hashedPassword, salt, err := s.hasher.HashPassword(password)
 	if err != nil {
 		return nil, err
 	}
@@ -46,7 +91,7 @@ func (s *UserService) Register(ctx context.Context, username, email, password, f
 		HashedPassword: hashedPassword,
 		Salt:           salt,
 		FullName:       fullName,
-		Role:           "user",
+		Role:           entities.RoleUser,
 		IsActive:       true,
 		EmailVerified:  false,
 		CreatedAt:      now,
@@ -58,43 +103,333 @@ func (s *UserService) Register(ctx context.Context, username, email, password, f
 		return nil, err
 	}
 
+	// Falha no envio não desfaz o cadastro: o usuário pode pedir o reenvio
+	_ = s.verification.SendVerificationEmail(ctx, user)
+
 	return user, nil
 }
 
-func (s *UserService) Login(ctx context.Context, email, password string) (string, string, error) {
+func (s *UserService) Login(ctx context.Context, email, password string, client ClientInfo) (*LoginResult, error) {
+	if err := s.loginGuard.Check(ctx, email, client); err != nil {
+		return nil, err
+	}
+
 	user, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
-		return "", "", errors.New("invalid credentials")
+		return nil, s.loginFailed(ctx, entities.AuthEventLoginFailed, email, nil, client, errors.New("invalid credentials"))
 	}
 
-	if !s.auth.VerifyPassword(password, user.HashedPassword, user.Salt) {
-		return "", "", errors.New("invalid credentials")
+	if !s.hasher.VerifyPassword(password, user.HashedPassword, user.Salt) {
+		return nil, s.loginFailed(ctx, entities.AuthEventLoginFailed, email, &user.ID, client, errors.New("invalid credentials"))
 	}
 
 	if !user.IsActive {
-		return "", "", errors.New("account is deactivated")
+		return nil, errors.New("account is deactivated")
+	}
+
+	// Aproveita que temos a senha em claro para migrar hashes legados (bcrypt)
+	// ou com parâmetros desatualizados para o argon2id atual
+	rehashed := false
+	if s.hasher.NeedsRehash(user.HashedPassword) {
+		hashedPassword, salt, err := s.hasher.HashPassword(password)
+		if err != nil {
+			return nil, err
+		}
+		user.HashedPassword = hashedPassword
+		user.Salt = salt
+		rehashed = true
+	}
+
+	if user.TOTPEnabled {
+		if rehashed {
+			user.UpdatedAt = time.Now()
+			if err := s.userRepo.Update(ctx, user); err != nil {
+				return nil, err
+			}
+		}
+
+		return s.mfaChallenge(user)
+	}
+
+	return s.completeLogin(ctx, user, client)
+}
+
+// LoginExternal abre a sessão de um usuário já autenticado por um provedor
+// externo. Quem tem 2FA ainda precisa passar por VerifyMFA.
+func (s *UserService) LoginExternal(ctx context.Context, user *entities.User, client ClientInfo) (*LoginResult, error) {
+	if !user.IsActive {
+		return nil, errors.New("account is deactivated")
+	}
+
+	if user.TOTPEnabled {
+		return s.mfaChallenge(user)
+	}
+
+	return s.completeLogin(ctx, user, client)
+}
+
+func (s *UserService) mfaChallenge(user *entities.User) (*LoginResult, error) {
+	mfaToken, err := s.tokens.GenerateMFAToken(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &LoginResult{MFARequired: true, MFAToken: mfaToken}, nil
+}
+
+// loginFailed contabiliza a tentativa e devolve o bloqueio, se ele foi
+// disparado, ou o erro original.
+func (s *UserService) loginFailed(ctx context.Context, eventType, email string, userID *uuid.UUID, client ClientInfo, cause error) error {
+	if err := s.loginGuard.Failure(ctx, eventType, email, userID, client); err != nil {
+		return err
+	}
+	return cause
+}
+
+// VerifyMFA conclui o login de quem tem 2FA, trocando o desafio emitido por
+// Login e um código TOTP (ou de recuperação) pelos tokens da sessão.
+func (s *UserService) VerifyMFA(ctx context.Context, mfaToken, code string, client ClientInfo) (*LoginResult, error) {
+	claims, err := s.tokens.ValidateMFAToken(mfaToken)
+	if err != nil {
+		return nil, errors.New("invalid or expired mfa token")
+	}
+
+	// O desafio vale para uma única tentativa: é consumido antes de conferir
+	// o código, para duas requisições simultâneas não usarem o mesmo desafio
+	claimed, err := s.denylist.ClaimToken(ctx, claims.ID, time.Until(claims.ExpiresAt.Time))
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, errors.New("invalid or expired mfa token")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, errors.New("invalid or expired mfa token")
+	}
+
+	if !user.IsActive {
+		return nil, errors.New("account is deactivated")
+	}
+	if !user.TOTPEnabled {
+		return nil, errors.New("two-factor authentication is not enabled")
+	}
+
+	// Códigos errados contam para o mesmo bloqueio da senha
+	if err := s.loginGuard.Check(ctx, user.Email, client); err != nil {
+		return nil, err
+	}
+
+	ok, err := s.twoFactor.VerifyCode(ctx, user, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, s.loginFailed(ctx, entities.AuthEventMFAFailed, user.Email, &user.ID, client, errors.New("invalid two-factor code"))
+	}
+
+	return s.completeLogin(ctx, user, client)
+}
+
+func (s *UserService) completeLogin(ctx context.Context, user *entities.User, client ClientInfo) (*LoginResult, error) {
+	if err := s.loginGuard.Success(ctx, user, client); err != nil {
+		return nil, err
 	}
 
 	now := time.Now()
 	user.LastLogin = &now
 	user.UpdatedAt = now
-	
-	err = s.userRepo.Update(ctx, user)
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	// Cada login inicia uma nova sessão, que é também a família dos refresh tokens
+	session := &entities.Session{
+		ID:         uuid.New(),
+		UserID:     user.ID,
+		UserAgent:  client.UserAgent,
+		IP:         client.IP,
+		CreatedAt:  now,
+		LastSeenAt: now,
+	}
+
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	accessToken, err := s.tokens.GenerateToken(user.ID, user.Role, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, token, err := s.newRefreshToken(user.ID, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.refreshTokenRepo.Create(ctx, token); err != nil {
+		return nil, err
+	}
+
+	return &LoginResult{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+func (s *UserService) Refresh(ctx context.Context, refreshToken string, client ClientInfo) (string, string, error) {
+	current, err := s.refreshTokenRepo.GetByHash(ctx, auth.HashToken(refreshToken))
 	if err != nil {
 		return "", "", err
 	}
+	if current == nil {
+		return "", "", errors.New("invalid refresh token")
+	}
 
-	accessToken, err := s.auth.GenerateToken(user.ID, user.Role)
+	// Um token já rotacionado sendo apresentado de novo indica que ele vazou:
+	// revoga a família inteira, derrubando também quem estiver com o sucessor
+	if current.RevokedAt != nil {
+		if err := s.revokeSession(ctx, current.FamilyID); err != nil {
+			return "", "", err
+		}
+		return "", "", errors.New("refresh token reuse detected")
+	}
+
+	if time.Now().After(current.ExpiresAt) {
+		return "", "", errors.New("refresh token expired")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, current.UserID)
+	if err != nil {
+		return "", "", errors.New("invalid refresh token")
+	}
+
+	if !user.IsActive {
+		return "", "", errors.New("account is deactivated")
+	}
+
+	accessToken, err := s.tokens.GenerateToken(user.ID, user.Role, current.FamilyID)
+	if err != nil {
+		return "", "", err
+	}
+
+	nextRefreshToken, next, err := s.newRefreshToken(user.ID, current.FamilyID)
 	if err != nil {
 		return "", "", err
 	}
 
-	refreshToken, err := s.auth.GenerateRefreshToken(user.ID)
+	rotated, err := s.refreshTokenRepo.Rotate(ctx, current.ID, next)
 	if err != nil {
 		return "", "", err
 	}
+	if !rotated {
+		if err := s.revokeSession(ctx, current.FamilyID); err != nil {
+			return "", "", err
+		}
+		return "", "", errors.New("refresh token reuse detected")
+	}
+
+	if err := s.sessionRepo.Touch(ctx, current.FamilyID, client.IP, client.UserAgent); err != nil {
+		return "", "", err
+	}
 
-	return accessToken, refreshToken, nil
+	return accessToken, nextRefreshToken, nil
+}
+
+// Logout revoga o access token atual e a sessão a que ele pertence. Tokens
+// emitidos antes das sessões não têm "sid"; nesse caso vale o refresh token
+// informado.
+func (s *UserService) Logout(ctx context.Context, userID uuid.UUID, tokenID string, expiresAt time.Time, sessionID uuid.UUID, refreshToken string) error {
+	if err := s.denylist.RevokeToken(ctx, tokenID, time.Until(expiresAt)); err != nil {
+		return err
+	}
+
+	if sessionID != uuid.Nil {
+		return s.revokeSession(ctx, sessionID)
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+
+	token, err := s.refreshTokenRepo.GetByHash(ctx, auth.HashToken(refreshToken))
+	if err != nil {
+		return err
+	}
+	if token == nil || token.UserID != userID {
+		return nil
+	}
+
+	return s.refreshTokenRepo.RevokeFamily(ctx, token.FamilyID)
+}
+
+// LogoutAll encerra todas as sessões do usuário, inclusive os access tokens
+// ainda válidos.
+func (s *UserService) LogoutAll(ctx context.Context, userID uuid.UUID) error {
+	if err := s.refreshTokenRepo.RevokeAllForUser(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := s.sessionRepo.RevokeAllForUser(ctx, userID); err != nil {
+		return err
+	}
+
+	return s.denylist.RevokeUserTokens(ctx, userID, time.Now(), s.tokens.AccessTokenTTL())
+}
+
+// ListSessions lista as sessões ativas, marcando a que fez a requisição.
+func (s *UserService) ListSessions(ctx context.Context, userID, currentSessionID uuid.UUID) ([]*entities.Session, error) {
+	sessions, err := s.sessionRepo.ListActiveByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, session := range sessions {
+		session.Current = session.ID == currentSessionID
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession encerra uma sessão do próprio usuário em outro dispositivo.
+func (s *UserService) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if session == nil || session.UserID != userID || session.RevokedAt != nil {
+		return errors.New("session not found")
+	}
+
+	return s.revokeSession(ctx, sessionID)
+}
+
+// revokeSession derruba os refresh tokens da sessão e, via denylist, os
+// access tokens que ainda não expiraram.
+func (s *UserService) revokeSession(ctx context.Context, sessionID uuid.UUID) error {
+	if err := s.refreshTokenRepo.RevokeFamily(ctx, sessionID); err != nil {
+		return err
+	}
+
+	if err := s.sessionRepo.Revoke(ctx, sessionID); err != nil {
+		return err
+	}
+
+	return s.denylist.RevokeSession(ctx, sessionID, s.tokens.AccessTokenTTL())
+}
+
+func (s *UserService) newRefreshToken(userID, familyID uuid.UUID) (string, *entities.RefreshToken, error) {
+	refreshToken, tokenHash, expiresAt, err := s.tokens.GenerateRefreshToken()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return refreshToken, &entities.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}, nil
 }
 
 func (s *UserService) GetByID(ctx context.Context, id uuid.UUID) (*entities.User, error) {
@@ -120,17 +455,74 @@ func (s *UserService) UpdateProfile(ctx context.Context, id uuid.UUID, fullName,
 	return user, nil
 }
 
+// ChangeUsername troca o nome do usuário, no máximo uma vez a cada
+// usernameCooldown. O nome antigo continua redirecionando para o novo
+// durante o mesmo período.
+func (s *UserService) ChangeUsername(ctx context.Context, id uuid.UUID, username string) (*entities.User, error) {
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if username == user.Username {
+		return nil, errors.New("new username must be different from the current one")
+	}
+
+	if err := validateUsername(username); err != nil {
+		return nil, err
+	}
+
+	last, err := s.historyRepo.GetLastByUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if last != nil {
+		if wait := time.Until(last.ChangedAt.Add(usernameCooldown)); wait > 0 {
+			return nil, &UsernameCooldownError{RetryAfter: wait}
+		}
+	}
+
+	// Mudar só maiúsculas e minúsculas não esbarra no próprio nome
+	if !strings.EqualFold(username, user.Username) {
+		if err := usernameAvailable(ctx, s.userRepo, s.historyRepo, username, id); err != nil {
+			return nil, err
+		}
+	}
+
+	change := &entities.UsernameChange{
+		ID:          uuid.New(),
+		UserID:      id,
+		OldUsername: user.Username,
+		NewUsername: username,
+		ChangedAt:   time.Now(),
+	}
+	if err := s.historyRepo.ChangeUsername(ctx, change); err != nil {
+		return nil, err
+	}
+
+	user.Username = username
+	user.UpdatedAt = change.ChangedAt
+
+	return user, nil
+}
+
 func (s *UserService) ChangePassword(ctx context.Context, id uuid.UUID, currentPassword, newPassword string) error {
 	user, err := s.userRepo.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
 
-	if !s.auth.VerifyPassword(currentPassword, user.HashedPassword, user.Salt) {
+	if !s.hasher.VerifyPassword(currentPassword, user.HashedPassword, user.Salt) {
 		return errors.New("current password is incorrect")
 	}
 
-	hashedPassword, salt, err := s.auth.HashPassword(newPassword)
+	return s.setPassword(ctx, user, newPassword)
+}
+
+// setPassword troca a senha e derruba todas as sessões abertas com a senha
+// antiga.
+func (s *UserService) setPassword(ctx context.Context, user *entities.User, newPassword string) error {
+	hashedPassword, salt, err := s.hasher.HashPassword(newPassword)
 	if err != nil {
 		return err
 	}
@@ -139,5 +531,9 @@ func (s *UserService) ChangePassword(ctx context.Context, id uuid.UUID, currentP
 	user.Salt = salt
 	user.UpdatedAt = time.Now()
 
-	return s.userRepo.Update(ctx, user)
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	return s.LogoutAll(ctx, user.ID)
 }
\ No newline at end of file
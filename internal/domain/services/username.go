@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/repositories"
+	"github.com/google/uuid"
+)
+
+// usernameCooldown é o intervalo mínimo entre duas trocas de nome e também
+// por quanto tempo o nome antigo redireciona para o novo e fica reservado.
+const usernameCooldown = 30 * 24 * time.Hour
+
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_]{3,20}$`)
+
+// Nomes que confundiriam rotas, a moderação ou o "[deleted]" dos autores apagados
+var reservedUsernames = map[string]bool{
+	"admin":         true,
+	"administrator": true,
+	"moderator":     true,
+	"deleted":       true,
+	"root":          true,
+	"support":       true,
+	"system":        true,
+}
+
+// UsernameCooldownError é retornado quando o usuário tenta trocar de nome
+// antes de passado o usernameCooldown da última troca.
+type UsernameCooldownError struct {
+	RetryAfter time.Duration
+}
+
+func (e *UsernameCooldownError) Error() string {
+	return fmt.Sprintf("username can only be changed once every %d days, try again in %s",
+		int(usernameCooldown.Hours()/24), e.RetryAfter.Round(time.Second))
+}
+
+// UsernameMovedError indica que o perfil buscado trocou de nome há pouco e
+// agora atende por Username.
+type UsernameMovedError struct {
+	Username string
+}
+
+func (e *UsernameMovedError) Error() string {
+	return fmt.Sprintf("user has been renamed to %s", e.Username)
+}
+
+func validateUsername(username string) error {
+	if !usernamePattern.MatchString(username) {
+		return errors.New("username must be 3 to 20 characters long and contain only letters, numbers and underscores")
+	}
+	if reservedUsernames[strings.ToLower(username)] {
+		return errors.New("username is reserved")
+	}
+	return nil
+}
+
+// usernameAvailable verifica se username está livre para userID (uuid.Nil no
+// cadastro): não pode pertencer a outra conta nem ter sido liberado por outro
+// usuário dentro do usernameCooldown.
+func usernameAvailable(
+	ctx context.Context,
+	userRepo repositories.UserRepository,
+	historyRepo repositories.UsernameHistoryRepository,
+	username string,
+	userID uuid.UUID,
+) error {
+	exists, err := userRepo.CheckUsernameExists(ctx, username)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return repositories.ErrUsernameTaken
+	}
+
+	recent, err := historyRepo.GetRecentByOldUsername(ctx, username, time.Now().Add(-usernameCooldown))
+	if err != nil {
+		return err
+	}
+	if recent != nil && recent.UserID != userID {
+		return repositories.ErrUsernameTaken
+	}
+
+	return nil
+}
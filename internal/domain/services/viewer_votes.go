@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/repositories"
+	"github.com/google/uuid"
+)
+
+// attachPostVotes preenche ViewerVote com o voto de viewerID em cada post.
+// Para visitantes anônimos (uuid.Nil) nada é preenchido.
+func attachPostVotes(ctx context.Context, voteRepo repositories.VoteRepository, viewerID uuid.UUID, posts ...*entities.Post) error {
+	if viewerID == uuid.Nil || len(posts) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(posts))
+	for i, post := range posts {
+		ids[i] = post.ID
+	}
+
+	votes, err := voteRepo.GetPostVotes(ctx, viewerID, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, post := range posts {
+		vote := votes[post.ID]
+		post.ViewerVote = &vote
+	}
+	return nil
+}
+
+func attachCommentVotes(ctx context.Context, voteRepo repositories.VoteRepository, viewerID uuid.UUID, comments ...*entities.Comment) error {
+	if viewerID == uuid.Nil || len(comments) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(comments))
+	for i, comment := range comments {
+		ids[i] = comment.ID
+	}
+
+	votes, err := voteRepo.GetCommentVotes(ctx, viewerID, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, comment := range comments {
+		vote := votes[comment.ID]
+		comment.ViewerVote = &vote
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/services"
+)
+
+type AdminHandler struct {
+	loginGuard           *services.LoginGuard
+	impersonationService *services.ImpersonationService
+}
+
+func NewAdminHandler(loginGuard *services.LoginGuard, impersonationService *services.ImpersonationService) *AdminHandler {
+	return &AdminHandler{
+		loginGuard:           loginGuard,
+		impersonationService: impersonationService,
+	}
+}
+
+type ImpersonateRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+func (h *AdminHandler) ListAuthEvents(c *gin.Context) {
+	userID, ok := userIDFilter(c)
+	if !ok {
+		return
+	}
+
+	limit, offset := getPaginationParams(c)
+
+	events, err := h.loginGuard.ListEvents(c.Request.Context(), userID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+func (h *AdminHandler) Impersonate(c *gin.Context) {
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	targetID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	var req ImpersonateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.impersonationService.Start(c.Request.Context(), actorID.(uuid.UUID), targetID, req.Reason, clientInfo(c))
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *AdminHandler) ListAuditLogs(c *gin.Context) {
+	userID, ok := userIDFilter(c)
+	if !ok {
+		return
+	}
+
+	limit, offset := getPaginationParams(c)
+
+	logs, err := h.impersonationService.ListAuditLogs(c.Request.Context(), userID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, logs)
+}
+
+// userIDFilter lê o filtro opcional ?user_id=; em caso de erro já responde
+// 400 e retorna false.
+func userIDFilter(c *gin.Context) (*uuid.UUID, bool) {
+	raw := c.Query("user_id")
+	if raw == "" {
+		return nil, true
+	}
+
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return nil, false
+	}
+	return &id, true
+}
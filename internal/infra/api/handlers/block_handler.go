@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/services"
+)
+
+type BlockHandler struct {
+	blockService *services.BlockService
+}
+
+func NewBlockHandler(blockService *services.BlockService) *BlockHandler {
+	return &BlockHandler{blockService: blockService}
+}
+
+func (h *BlockHandler) Block(c *gin.Context) {
+	h.create(c, entities.BlockKindBlock)
+}
+
+func (h *BlockHandler) Unblock(c *gin.Context) {
+	h.delete(c, entities.BlockKindBlock)
+}
+
+func (h *BlockHandler) Mute(c *gin.Context) {
+	h.create(c, entities.BlockKindMute)
+}
+
+func (h *BlockHandler) Unmute(c *gin.Context) {
+	h.delete(c, entities.BlockKindMute)
+}
+
+func (h *BlockHandler) ListBlocks(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	blocks, err := h.blockService.ListBlocks(c.Request.Context(), userID.(uuid.UUID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, blocks)
+}
+
+func (h *BlockHandler) create(c *gin.Context, kind string) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if err := h.blockService.Block(c.Request.Context(), userID.(uuid.UUID), c.Param("username"), kind); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *BlockHandler) delete(c *gin.Context, kind string) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if err := h.blockService.Unblock(c.Request.Context(), userID.(uuid.UUID), c.Param("username"), kind); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
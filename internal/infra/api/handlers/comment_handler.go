@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -100,41 +101,123 @@ func (h *CommentHandler) DeleteComment(c *gin.Context) {
 }
 
 func (h *CommentHandler) GetCommentsByPost(c *gin.Context) {
-	postID, err := uuid.Parse(c.Param("post_id"))
+	postID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post ID"})
 		return
 	}
 
+	sort, err := services.ParseCommentSort(c.Query("sort"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	limit, offset := getPaginationParams(c)
 
-	comments, err := h.commentService.GetCommentsByPost(c.Request.Context(), postID, limit, offset)
+	comments, err := h.commentService.GetCommentsByPost(c.Request.Context(), postID, viewerID(c), sort, limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, comments)
 }
 
+// GetCommentTree aceita ?sort=, ?depth=, ?limit= (primeiro nível),
+// ?children= (demais níveis) e ?continue= (o token de um "more"). Com
+// ?comment=, devolve o permalink do comentário com ?context= ancestrais.
+func (h *CommentHandler) GetCommentTree(c *gin.Context) {
+	postID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post ID"})
+		return
+	}
+
+	sort, err := services.ParseCommentSort(c.Query("sort"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	opts := services.CommentTreeOptions{Sort: sort, Continuation: c.Query("continue")}
+	opts.Depth, _ = strconv.Atoi(c.Query("depth"))
+	opts.Limit, _ = strconv.Atoi(c.Query("limit"))
+	opts.Children, _ = strconv.Atoi(c.Query("children"))
+	opts.Context, _ = strconv.Atoi(c.Query("context"))
+
+	if raw := c.Query("comment"); raw != "" {
+		commentID, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid comment ID"})
+			return
+		}
+		opts.CommentID = &commentID
+	}
+
+	tree, err := h.commentService.GetCommentTree(c.Request.Context(), postID, viewerID(c), opts)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidContinuation) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tree)
+}
+
 func (h *CommentHandler) GetReplies(c *gin.Context) {
-	parentID, err := uuid.Parse(c.Param("parentID"))
+	parentID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid parent comment ID"})
 		return
 	}
 
+	sort, err := services.ParseCommentSort(c.Query("sort"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	limit, offset := getPaginationParams(c)
 
-	replies, err := h.commentService.GetReplies(c.Request.Context(), parentID, limit, offset)
+	replies, err := h.commentService.GetReplies(c.Request.Context(), parentID, viewerID(c), sort, limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, replies)
 }
 
+func (h *CommentHandler) VoteComment(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	commentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid comment ID"})
+		return
+	}
+
+	var req VoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	comment, err := h.commentService.VoteComment(c.Request.Context(), commentID, userID.(uuid.UUID), *req.Dir)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, comment)
+}
+
 func getPaginationParams(c *gin.Context) (int, int) {
 	limit := c.DefaultQuery("limit", "10")
 	offset := c.DefaultQuery("offset", "0")
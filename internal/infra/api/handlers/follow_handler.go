@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/services"
+)
+
+type FollowHandler struct {
+	followService *services.FollowService
+}
+
+func NewFollowHandler(followService *services.FollowService) *FollowHandler {
+	return &FollowHandler{followService: followService}
+}
+
+func (h *FollowHandler) Follow(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if err := h.followService.Follow(c.Request.Context(), userID.(uuid.UUID), c.Param("username")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *FollowHandler) Unfollow(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if err := h.followService.Unfollow(c.Request.Context(), userID.(uuid.UUID), c.Param("username")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetFollowingFeed aceita ?cursor= (o next_cursor da página anterior) e ?limit=.
+func (h *FollowHandler) GetFollowingFeed(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	page, err := h.followService.Feed(c.Request.Context(), userID.(uuid.UUID), c.Query("cursor"), limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/infra/auth"
+)
+
+type JWKSHandler struct {
+	jwtService *auth.JWTService
+}
+
+func NewJWKSHandler(jwtService *auth.JWTService) *JWKSHandler {
+	return &JWKSHandler{jwtService: jwtService}
+}
+
+func (h *JWKSHandler) GetJWKS(c *gin.Context) {
+	// Cache curto: uma chave nova precisa aparecer logo para quem valida
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.jwtService.JWKS())
+}
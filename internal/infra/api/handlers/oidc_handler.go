@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/services"
+)
+
+const (
+	oidcStateCookie     = "oidc_state"
+	oidcStateCookiePath = "/auth/oidc"
+	oidcStateCookieAge  = 600
+)
+
+type OIDCHandler struct {
+	oidcService   *services.OIDCService
+	secureCookies bool
+}
+
+func NewOIDCHandler(oidcService *services.OIDCService, secureCookies bool) *OIDCHandler {
+	return &OIDCHandler{
+		oidcService:   oidcService,
+		secureCookies: secureCookies,
+	}
+}
+
+// Begin redireciona para o provedor, guardando o state assinado num cookie
+// que só volta para o callback.
+func (h *OIDCHandler) Begin(c *gin.Context) {
+	login, err := h.oidcService.Begin(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, login.StateToken, oidcStateCookieAge, oidcStateCookiePath, "", h.secureCookies, true)
+	c.Redirect(http.StatusFound, login.AuthURL)
+}
+
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	stateToken, _ := c.Cookie(oidcStateCookie)
+	// O state vale para uma única tentativa
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, "", -1, oidcStateCookiePath, "", h.secureCookies, true)
+
+	if providerError := c.Query("error"); providerError != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "identity provider returned an error: " + providerError})
+		return
+	}
+
+	code := c.Query("code")
+	if code == "" || stateToken == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "missing authorization code or login state"})
+		return
+	}
+
+	result, err := h.oidcService.Callback(
+		c.Request.Context(),
+		c.Param("provider"),
+		code,
+		c.Query("state"),
+		stateToken,
+		clientInfo(c),
+	)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
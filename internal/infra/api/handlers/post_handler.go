@@ -28,6 +28,18 @@ type UpdatePostRequest struct {
 	Content string `json:"content"`
 }
 
+// SuggestedSortRequest é o corpo de PUT /posts/:id/suggested-sort; sort
+// vazio remove a sugestão.
+type SuggestedSortRequest struct {
+	Sort string `json:"sort"`
+}
+
+// VoteRequest é o corpo de PUT /posts/:id/vote e PUT /comments/:id/vote:
+// dir 1 (upvote), -1 (downvote) ou 0 para remover o voto.
+type VoteRequest struct {
+	Dir *int `json:"dir" binding:"required,oneof=-1 0 1"`
+}
+
 func (h *PostHandler) CreatePost(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
@@ -97,4 +109,110 @@ func (h *PostHandler) DeletePost(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusNoContent, nil)
-}
\ No newline at end of file
+}
+
+func (h *PostHandler) GetPost(c *gin.Context) {
+	postID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post ID"})
+		return
+	}
+
+	post, err := h.postService.GetPost(c.Request.Context(), postID, viewerID(c))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, post)
+}
+
+func (h *PostHandler) GetPostsBySub(c *gin.Context) {
+	listing, err := services.ParsePostListing(c.Query("sort"), c.Query("t"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	limit, offset := getPaginationParams(c)
+
+	posts, err := h.postService.GetPostsBySubName(c.Request.Context(), c.Param("name"), viewerID(c), listing, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, posts)
+}
+
+func (h *PostHandler) GetFrontPage(c *gin.Context) {
+	listing, err := services.ParsePostListing(c.Query("sort"), c.Query("t"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	limit, offset := getPaginationParams(c)
+
+	posts, err := h.postService.GetFrontPage(c.Request.Context(), viewerID(c), listing, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, posts)
+}
+
+func (h *PostHandler) VotePost(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	postID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post ID"})
+		return
+	}
+
+	var req VoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	post, err := h.postService.VotePost(c.Request.Context(), postID, userID.(uuid.UUID), *req.Dir)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, post)
+}
+
+func (h *PostHandler) SetSuggestedSort(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	postID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post ID"})
+		return
+	}
+
+	var req SuggestedSortRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	post, err := h.postService.SetSuggestedSort(c.Request.Context(), postID, userID.(uuid.UUID), req.Sort)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, post)
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/services"
+)
+
+type ProfileHandler struct {
+	profileService *services.ProfileService
+}
+
+func NewProfileHandler(profileService *services.ProfileService) *ProfileHandler {
+	return &ProfileHandler{profileService: profileService}
+}
+
+func (h *ProfileHandler) GetProfile(c *gin.Context) {
+	profile, err := h.profileService.GetProfile(c.Request.Context(), c.Param("username"))
+	if err != nil {
+		respondProfileError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+func (h *ProfileHandler) GetPosts(c *gin.Context) {
+	limit, offset := getPaginationParams(c)
+
+	posts, err := h.profileService.GetPosts(c.Request.Context(), c.Param("username"), limit, offset)
+	if err != nil {
+		respondProfileError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, posts)
+}
+
+func (h *ProfileHandler) GetComments(c *gin.Context) {
+	limit, offset := getPaginationParams(c)
+
+	comments, err := h.profileService.GetComments(c.Request.Context(), c.Param("username"), limit, offset)
+	if err != nil {
+		respondProfileError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, comments)
+}
+
+// respondProfileError redireciona nomes trocados recentemente para o perfil
+// com o nome atual, mantendo o resto do caminho e a query.
+func respondProfileError(c *gin.Context, err error) {
+	var moved *services.UsernameMovedError
+	if errors.As(err, &moved) {
+		location := strings.Replace(c.Request.URL.Path, "/users/"+c.Param("username"), "/users/"+moved.Username, 1)
+		if c.Request.URL.RawQuery != "" {
+			location += "?" + c.Request.URL.RawQuery
+		}
+		c.Redirect(http.StatusFound, location)
+		return
+	}
+
+	c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+}
@@ -23,6 +23,7 @@ type CreateSubRequest struct {
 	Description string   `json:"description" binding:"required"`
 	Rules       []string `json:"rules" binding:"required"`
 	IsPrivate   bool     `json:"is_private"`
+	MinKarma    int      `json:"min_karma"`
 }
 
 type UpdateSubRequest struct {
@@ -31,6 +32,7 @@ type UpdateSubRequest struct {
 	IsPrivate   bool     `json:"is_private"`
 	BannerURL   string   `json:"banner_url"`
 	IconURL     string   `json:"icon_url"`
+	MinKarma    int      `json:"min_karma"`
 }
 
 func (h *SubHandler) createSub(ctx *gin.Context) {
@@ -53,6 +55,7 @@ func (h *SubHandler) createSub(ctx *gin.Context) {
 		createReq.Rules,
 		userID.(uuid.UUID),
 		createReq.IsPrivate,
+		createReq.MinKarma,
 	)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err.Error()))
@@ -94,6 +97,7 @@ func (h *SubHandler) UpdateSub(c *gin.Context) {
 		updateReq.IsPrivate,
 		updateReq.BannerURL,
 		updateReq.IconURL,
+		updateReq.MinKarma,
 	)
 	if updateErr != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": updateErr.Error()})
@@ -149,7 +153,7 @@ func (h *SubHandler) GetSubByName(c *gin.Context) {
 
 	sub, err := h.subService.GetSubByName(c.Request.Context(), name)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -218,6 +222,7 @@ func (h *SubHandler) CreateSub(c *gin.Context) {
 	createReq.Rules,
 	userID.(uuid.UUID),
 	createReq.IsPrivate,
+	createReq.MinKarma,
 )
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -226,3 +231,68 @@ func (h *SubHandler) CreateSub(c *gin.Context) {
 
 	c.JSON(http.StatusCreated, sub)
 }
+
+type BanUserRequest struct {
+	Reason string `json:"reason"`
+}
+
+func (h *SubHandler) BanUser(c *gin.Context) {
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	subID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sub ID"})
+		return
+	}
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	var req BanUserRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ban, err := h.subService.BanUser(c.Request.Context(), subID, actorID.(uuid.UUID), userID, req.Reason)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, ban)
+}
+
+func (h *SubHandler) UnbanUser(c *gin.Context) {
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	subID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sub ID"})
+		return
+	}
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	if err := h.subService.UnbanUser(c.Request.Context(), subID, actorID.(uuid.UUID), userID); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
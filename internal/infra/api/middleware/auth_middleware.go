@@ -1,21 +1,35 @@
 package middleware
 
 import (
-	"fmt"
+	"context"
 	"net/http"
 	"strings"
 
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/services"
 	"github.com/elaurentium/exilium-blog-backend/internal/infra/auth"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type AuthMiddleware struct {
-	jwtService *auth.JWTService
+	jwtService           *auth.JWTService
+	denylist             auth.TokenDenylist
+	apiTokenService      *services.APITokenService
+	impersonationService *services.ImpersonationService
 }
 
-func NewAuthMiddleware(jwtService *auth.JWTService) *AuthMiddleware {
+func NewAuthMiddleware(
+	jwtService *auth.JWTService,
+	denylist auth.TokenDenylist,
+	apiTokenService *services.APITokenService,
+	impersonationService *services.ImpersonationService,
+) *AuthMiddleware {
 	return &AuthMiddleware{
-		jwtService: jwtService,
+		jwtService:           jwtService,
+		denylist:             denylist,
+		apiTokenService:      apiTokenService,
+		impersonationService: impersonationService,
 	}
 }
 
@@ -34,21 +48,149 @@ func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 		}
 
 		tokenString := parts[1]
-		fmt.Printf("token %s\n", tokenString)
+		if auth.IsAPIToken(tokenString) {
+			m.authenticateAPIToken(c, tokenString)
+			return
+		}
+
 		claims, err := m.jwtService.ValidateToken(tokenString)
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
 			return
 		}
 
+		revoked, err := m.isRevoked(c, claims)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			return
+		}
+		if revoked {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "token has been revoked"})
+			return
+		}
+
 		// Adiciona as claims ao contexto
-		c.Set("userID", claims.UserID)
+		c.Set("user_id", claims.UserID)
 		c.Set("role", claims.Role)
-		
+		c.Set("token_id", claims.ID)
+		c.Set("token_expires_at", claims.ExpiresAt.Time)
+		if claims.SessionID != nil {
+			c.Set("session_id", *claims.SessionID)
+		}
+
+		if claims.Actor != nil {
+			m.trackImpersonation(c, claims)
+			return
+		}
+
 		c.Next()
 	}
 }
 
+// OptionalAuthenticate identifica o usuário quando há um token, mas deixa
+// passar requisições anônimas. Um token inválido continua sendo recusado,
+// para o cliente saber que precisa renová-lo.
+func (m *AuthMiddleware) OptionalAuthenticate() gin.HandlerFunc {
+	authenticate := m.Authenticate()
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+
+		authenticate(c)
+	}
+}
+
+// authenticateAPIToken trata os tokens de acesso pessoal. Eles não carregam
+// token_id: rotas de sessão (logout, perfil, gestão de tokens) usam
+// RequireSession para recusá-los.
+func (m *AuthMiddleware) authenticateAPIToken(c *gin.Context, tokenString string) {
+	token, user, err := m.apiTokenService.Authenticate(c.Request.Context(), tokenString)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
+		return
+	}
+
+	c.Set("user_id", user.ID)
+	c.Set("role", user.Role)
+	c.Set("api_token_id", token.ID)
+	c.Set("scopes", token.Scopes)
+
+	c.Next()
+}
+
+// trackImpersonation sinaliza na resposta que o token é de impersonação e,
+// depois que o handler responde, registra no audit log toda requisição que
+// pode alterar dados junto com o status devolvido.
+func (m *AuthMiddleware) trackImpersonation(c *gin.Context, claims *auth.Claims) {
+	actorID := claims.Actor.UserID
+	c.Set("actor_id", actorID)
+	c.Header("X-Impersonated-By", actorID.String())
+
+	c.Next()
+
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return
+	}
+
+	// A resposta já foi enviada: o registro não pode ser cancelado junto com
+	// a conexão, e uma falha só é anotada no contexto
+	err := m.impersonationService.RecordRequest(
+		context.WithoutCancel(c.Request.Context()),
+		actorID,
+		claims.UserID,
+		c.Request.Method,
+		c.Request.URL.Path,
+		c.Writer.Status(),
+		services.ClientInfo{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()},
+	)
+	if err != nil {
+		c.Error(err)
+	}
+}
+
+func (m *AuthMiddleware) isRevoked(c *gin.Context, claims *auth.Claims) (bool, error) {
+	ctx := c.Request.Context()
+
+	if claims.ID != "" {
+		revoked, err := m.denylist.IsTokenRevoked(ctx, claims.ID)
+		if err != nil || revoked {
+			return revoked, err
+		}
+	}
+
+	if claims.SessionID != nil {
+		revoked, err := m.denylist.IsSessionRevoked(ctx, *claims.SessionID)
+		if err != nil || revoked {
+			return revoked, err
+		}
+	}
+
+	// Tokens de impersonação também caem quando o admin encerra as sessões dele
+	if claims.Actor != nil {
+		revoked, err := m.revokedForUser(c, claims, claims.Actor.UserID)
+		if err != nil || revoked {
+			return revoked, err
+		}
+	}
+
+	return m.revokedForUser(c, claims, claims.UserID)
+}
+
+// revokedForUser aplica o "logout de todas as sessões": tokens emitidos
+// antes dele não valem mais (ver auth.RevokedByCutoff).
+func (m *AuthMiddleware) revokedForUser(c *gin.Context, claims *auth.Claims, userID uuid.UUID) (bool, error) {
+	revokedBefore, err := m.denylist.UserTokensRevokedBefore(c.Request.Context(), userID)
+	if err != nil {
+		return false, err
+	}
+	return auth.RevokedByCutoff(claims.IssuedAt, revokedBefore), nil
+}
+
+// RequireRole aceita o papel informado ou qualquer papel acima dele na
+// hierarquia (user < moderator < admin).
 func (m *AuthMiddleware) RequireRole(roles ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		role, exists := c.Get("role")
@@ -60,7 +202,7 @@ func (m *AuthMiddleware) RequireRole(roles ...string) gin.HandlerFunc {
 		userRole := role.(string)
 		allowed := false
 		for _, r := range roles {
-			if r == userRole {
+			if entities.RoleAtLeast(userRole, r) {
 				allowed = true
 				break
 			}
@@ -71,6 +213,69 @@ func (m *AuthMiddleware) RequireRole(roles ...string) gin.HandlerFunc {
 			return
 		}
 
+		c.Next()
+	}
+}
+
+// RequireScope exige o escopo quando a requisição usa um token de acesso
+// pessoal. Sessões JWT têm acesso a todos os escopos.
+func (m *AuthMiddleware) RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scopes, isAPIToken := c.Get("scopes")
+		if !isAPIToken {
+			c.Next()
+			return
+		}
+
+		for _, s := range scopes.([]string) {
+			if s == scope {
+				c.Next()
+				return
+			}
+		}
+
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "token is missing required scope: " + scope})
+	}
+}
+
+// RequireSession recusa tokens de acesso pessoal.
+func (m *AuthMiddleware) RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, isAPIToken := c.Get("api_token_id"); isAPIToken {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "this endpoint requires a user session"})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func (m *AuthMiddleware) RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, exists := c.Get("role")
+		if !exists {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		if !entities.HasPermission(role.(string), permission) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// DenyImpersonation bloqueia ações sensíveis (senha, 2FA, tokens, sessões)
+// para quem está agindo como outro usuário.
+func (m *AuthMiddleware) DenyImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, impersonating := c.Get("actor_id"); impersonating {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "this action is not allowed while impersonating"})
+			return
+		}
+
 		c.Next()
 	}
 }
\ No newline at end of file
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -103,7 +104,7 @@ func (m *RateLimiterMiddleware) RedisRateLimiter(endpoint string, limit int, win
 			return
 		}
 		
-		c.Header("X-RateLimit-Remaining", string(int64(limit)-val))
+		c.Header("X-RateLimit-Remaining", strconv.FormatInt(int64(limit)-val, 10))
 		c.Next()
 	}
 }
@@ -2,6 +2,7 @@ package api
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
 	"github.com/elaurentium/exilium-blog-backend/internal/infra/api/handlers"
 	"github.com/elaurentium/exilium-blog-backend/internal/infra/api/middleware"
 	"github.com/elaurentium/exilium-blog-backend/internal/infra/persistence/redis"
@@ -13,6 +14,16 @@ func NewRouter(
 	postHandler *handlers.PostHandler,
 	commentHandler *handlers.CommentHandler,
 	subHandler *handlers.SubHandler,
+	passwordHandler *handlers.PasswordHandler,
+	jwksHandler *handlers.JWKSHandler,
+	twoFactorHandler *handlers.TwoFactorHandler,
+	adminHandler *handlers.AdminHandler,
+	apiTokenHandler *handlers.APITokenHandler,
+	oidcHandler *handlers.OIDCHandler,
+	profileHandler *handlers.ProfileHandler,
+	accountHandler *handlers.AccountHandler,
+	followHandler *handlers.FollowHandler,
+	blockHandler *handlers.BlockHandler,
 	authMiddleware *middleware.AuthMiddleware,
 	redisClient *redis.RedisClient,
 ) *gin.Engine {
@@ -27,24 +38,101 @@ func NewRouter(
 	router.Use(middleware.NewRateLimiterMiddleware(redisClient.GetClient()))
 
 	// Public routes
+	router.GET("/.well-known/jwks.json", jwksHandler.GetJWKS)
 	router.POST("/register", userHandler.Register)
 	router.POST("/login", userHandler.Login)
+	router.POST("/auth/mfa/verify", userHandler.VerifyMFA)
+	router.POST("/auth/refresh", userHandler.Refresh)
+	router.GET("/auth/oidc/:provider", oidcHandler.Begin)
+	router.GET("/auth/oidc/:provider/callback", oidcHandler.Callback)
+	router.POST("/auth/verify-email", userHandler.VerifyEmail)
+	router.POST("/auth/password/forgot", passwordHandler.ForgotPassword)
+	router.POST("/auth/password/reset", passwordHandler.ResetPassword)
+	router.GET("/users/:username", profileHandler.GetProfile)
+	router.GET("/users/:username/posts", profileHandler.GetPosts)
+	router.GET("/users/:username/comments", profileHandler.GetComments)
 
-	// Protected routes
+	// Public read routes (anonymous, or authenticated to include the viewer's votes)
+	readGroup := router.Group("/")
+	readGroup.Use(authMiddleware.OptionalAuthenticate())
+	{
+		readGroup.GET("/posts", postHandler.GetFrontPage)
+		readGroup.GET("/posts/trending", postHandler.GetFrontPage)
+		readGroup.GET("/posts/:id", postHandler.GetPost)
+		readGroup.GET("/posts/:id/comments", commentHandler.GetCommentsByPost)
+		readGroup.GET("/posts/:id/comments/tree", commentHandler.GetCommentTree)
+		readGroup.GET("/comments/:id/replies", commentHandler.GetReplies)
+		readGroup.GET("/subs", subHandler.ListSubs)
+		readGroup.GET("/subs/trending", subHandler.GetTrendingSubreddits)
+		readGroup.GET("/subs/:name", subHandler.GetSubByName)
+		readGroup.GET("/subs/:name/posts", postHandler.GetPostsBySub)
+	}
+
+	// Protected routes (JWT or personal access token with the matching scope)
 	authGroup := router.Group("/")
 	authGroup.Use(authMiddleware.Authenticate(), authMiddleware.RequireRole("user"))
 	{
-		authGroup.GET("/profile", userHandler.GetProfile)
-		authGroup.PUT("/profile", userHandler.UpdateProfile)
-		authGroup.POST("/posts", postHandler.CreatePost)
-		authGroup.PUT("/posts/:id", postHandler.UpdatePost)
-		authGroup.DELETE("/posts/:id", postHandler.DeletePost)
-		authGroup.POST("/comments", commentHandler.CreateComment)
-		authGroup.PUT("/comments/:id", commentHandler.UpdateComment)
-		authGroup.DELETE("/comments/:id", commentHandler.DeleteComment)
-		authGroup.POST("/sub", subHandler.CreateSub)
-		authGroup.PUT("/sub/:id", subHandler.UpdateSub)
-		authGroup.DELETE("/sub/:id", subHandler.DeleteSub)
+		authGroup.POST("/posts", authMiddleware.RequireScope(entities.ScopePostsWrite), postHandler.CreatePost)
+		authGroup.PUT("/posts/:id", authMiddleware.RequireScope(entities.ScopePostsWrite), postHandler.UpdatePost)
+		authGroup.DELETE("/posts/:id", authMiddleware.RequireScope(entities.ScopePostsWrite), postHandler.DeletePost)
+		authGroup.PUT("/posts/:id/vote", authMiddleware.RequireScope(entities.ScopeVotesWrite), postHandler.VotePost)
+		authGroup.PUT("/posts/:id/suggested-sort", authMiddleware.RequireScope(entities.ScopePostsWrite), postHandler.SetSuggestedSort)
+		authGroup.POST("/comments", authMiddleware.RequireScope(entities.ScopeCommentsWrite), commentHandler.CreateComment)
+		authGroup.PUT("/comments/:id", authMiddleware.RequireScope(entities.ScopeCommentsWrite), commentHandler.UpdateComment)
+		authGroup.DELETE("/comments/:id", authMiddleware.RequireScope(entities.ScopeCommentsWrite), commentHandler.DeleteComment)
+		authGroup.PUT("/comments/:id/vote", authMiddleware.RequireScope(entities.ScopeVotesWrite), commentHandler.VoteComment)
+		authGroup.POST("/sub", authMiddleware.RequireScope(entities.ScopeSubsWrite), subHandler.CreateSub)
+		authGroup.PUT("/sub/:id", authMiddleware.RequireScope(entities.ScopeSubsWrite), subHandler.UpdateSub)
+		authGroup.DELETE("/sub/:id", authMiddleware.RequireScope(entities.ScopeSubsWrite), subHandler.DeleteSub)
+		authGroup.PUT("/sub/:id/bans/:user_id", authMiddleware.RequireScope(entities.ScopeSubsWrite), subHandler.BanUser)
+		authGroup.DELETE("/sub/:id/bans/:user_id", authMiddleware.RequireScope(entities.ScopeSubsWrite), subHandler.UnbanUser)
+	}
+
+	// Account routes (JWT session only)
+	sessionGroup := router.Group("/")
+	sessionGroup.Use(authMiddleware.Authenticate(), authMiddleware.RequireSession(), authMiddleware.RequireRole("user"))
+	{
+		sessionGroup.POST("/auth/logout", userHandler.Logout)
+		sessionGroup.GET("/profile", userHandler.GetProfile)
+		sessionGroup.PUT("/profile", userHandler.UpdateProfile)
+		sessionGroup.POST("/users/:username/follow", followHandler.Follow)
+		sessionGroup.DELETE("/users/:username/follow", followHandler.Unfollow)
+		sessionGroup.GET("/feed/following", followHandler.GetFollowingFeed)
+		sessionGroup.POST("/users/:username/block", blockHandler.Block)
+		sessionGroup.DELETE("/users/:username/block", blockHandler.Unblock)
+		sessionGroup.POST("/users/:username/mute", blockHandler.Mute)
+		sessionGroup.DELETE("/users/:username/mute", blockHandler.Unmute)
+		sessionGroup.GET("/blocks", blockHandler.ListBlocks)
+	}
+
+	// Sensitive account routes (not available while impersonating)
+	accountGroup := sessionGroup.Group("/")
+	accountGroup.Use(authMiddleware.DenyImpersonation())
+	{
+		accountGroup.POST("/auth/logout-all", userHandler.LogoutAll)
+		accountGroup.POST("/auth/verify-email/resend", userHandler.ResendVerificationEmail)
+		accountGroup.POST("/auth/2fa/enroll", twoFactorHandler.Enroll)
+		accountGroup.POST("/auth/2fa/confirm", twoFactorHandler.Confirm)
+		accountGroup.POST("/auth/2fa/disable", twoFactorHandler.Disable)
+		accountGroup.POST("/auth/2fa/recovery-codes", twoFactorHandler.RegenerateRecoveryCodes)
+		accountGroup.PUT("/profile/password", userHandler.ChangePassword)
+		accountGroup.PUT("/profile/username", userHandler.ChangeUsername)
+		accountGroup.DELETE("/profile", accountHandler.DeleteAccount)
+		accountGroup.GET("/profile/export", accountHandler.ExportData)
+		accountGroup.GET("/sessions", userHandler.ListSessions)
+		accountGroup.DELETE("/sessions/:id", userHandler.RevokeSession)
+		accountGroup.GET("/tokens", apiTokenHandler.ListTokens)
+		accountGroup.POST("/tokens", apiTokenHandler.CreateToken)
+		accountGroup.DELETE("/tokens/:id", apiTokenHandler.RevokeToken)
+	}
+
+	// Admin routes
+	adminGroup := router.Group("/admin")
+	adminGroup.Use(authMiddleware.Authenticate(), authMiddleware.RequireSession(), authMiddleware.DenyImpersonation(), authMiddleware.RequireRole(entities.RoleModerator))
+	{
+		adminGroup.GET("/auth-events", authMiddleware.RequirePermission(entities.PermissionAuthEventsRead), adminHandler.ListAuthEvents)
+		adminGroup.GET("/audit-logs", authMiddleware.RequirePermission(entities.PermissionAuditLogRead), adminHandler.ListAuditLogs)
+		adminGroup.POST("/impersonate/:user_id", authMiddleware.RequirePermission(entities.PermissionUserImpersonate), adminHandler.Impersonate)
 	}
 
 	return router
@@ -1,64 +0,0 @@
-package auth
-
-import (
-	"crypto/rand"
-	"encoding/base64"
-	"time"
-
-	"github.com/golang-jwt/jwt"
-	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
-)
-
-type AuthService interface {
-	HashPassword(password string) (string, string, error)
-	VerifyPassword(password, hashedPassword, salt string) bool
-	GenerateToken(userID uuid.UUID, role string) (string, error)
-	GenerateRefreshToken(userID uuid.UUID) (string, error)
-}
-
-type authService struct {
-	secretKey []byte
-}
-
-func NewAuthService() AuthService {
-	return &authService{secretKey: []byte("your-secret-key-here")} // Replace with a secure key
-}
-
-func (a *authService) HashPassword(password string) (string, string, error) {
-	saltBytes := make([]byte, 16)
-	_, err := rand.Read(saltBytes)
-	if err != nil {
-		return "", "", err
-	}
-	salt := base64.StdEncoding.EncodeToString(saltBytes)
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password+salt), bcrypt.DefaultCost)
-	if err != nil {
-		return "", "", err
-	}
-	return string(hashedPassword), salt, nil
-}
-
-func (a *authService) VerifyPassword(password, hashedPassword, salt string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password+salt))
-	return err == nil
-}
-
-func (a *authService) GenerateToken(userID uuid.UUID, role string) (string, error) {
-	claims := jwt.MapClaims{
-		"user_id": userID.String(),
-		"role":    role,
-		"exp":     time.Now().Add(time.Hour * 24).Unix(),
-	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(a.secretKey)
-}
-
-func (a *authService) GenerateRefreshToken(userID uuid.UUID) (string, error) {
-	claims := jwt.MapClaims{
-		"user_id": userID.String(),
-		"exp":     time.Now().Add(time.Hour * 24 * 7).Unix(),
-	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(a.secretKey)
-}
\ No newline at end of file
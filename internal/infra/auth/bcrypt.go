@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// BcryptHasher é o formato antigo: bcrypt sobre a senha concatenada com um
+// salt próprio guardado na coluna users.salt. Mantido apenas para validar
+// senhas ainda não migradas para argon2id.
+type BcryptHasher struct{}
+
+func NewBcryptHasher() *BcryptHasher {
+	return &BcryptHasher{}
+}
+
+func (h *BcryptHasher) HashPassword(password string) (string, string, error) {
+	saltBytes := make([]byte, 16)
+	_, err := rand.Read(saltBytes)
+	if err != nil {
+		return "", "", err
+	}
+	salt := base64.StdEncoding.EncodeToString(saltBytes)
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password+salt), bcrypt.DefaultCost)
+	if err != nil {
+		return "", "", err
+	}
+	return string(hashedPassword), salt, nil
+}
+
+func (h *BcryptHasher) VerifyPassword(password, hashedPassword, salt string) bool {
+	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password+salt))
+	return err == nil
+}
+
+func (h *BcryptHasher) Supports(hashedPassword string) bool {
+	return strings.HasPrefix(hashedPassword, "$2a$") ||
+		strings.HasPrefix(hashedPassword, "$2b$") ||
+		strings.HasPrefix(hashedPassword, "$2y$")
+}
+
+func (h *BcryptHasher) NeedsRehash(hashedPassword string) bool {
+	cost, err := bcrypt.Cost([]byte(hashedPassword))
+	return err != nil || cost < bcrypt.DefaultCost
+}
@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// CooldownStore marca ações que só podem se repetir depois de um intervalo,
+// como o reenvio do e-mail de verificação.
+type CooldownStore interface {
+	// Start inicia o intervalo de key. Se o anterior ainda não acabou, nada
+	// muda e Start devolve quanto falta.
+	Start(ctx context.Context, key string, d time.Duration) (time.Duration, error)
+}
+
+// MemoryCooldownStore é a implementação em memória, para testes e para rodar
+// sem Redis.
+type MemoryCooldownStore struct {
+	mu      sync.Mutex
+	expires map[string]time.Time
+}
+
+func NewMemoryCooldownStore() *MemoryCooldownStore {
+	return &MemoryCooldownStore{expires: make(map[string]time.Time)}
+}
+
+func (s *MemoryCooldownStore) Start(ctx context.Context, key string, d time.Duration) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if wait := s.expires[key].Sub(now); wait > 0 {
+		return wait, nil
+	}
+	s.expires[key] = now.Add(d)
+	return 0, nil
+}
@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryCooldownStoreStart(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		setup    func(s *MemoryCooldownStore)
+		wantWait bool
+	}{
+		{name: "first start"},
+		{
+			name:     "cooldown running",
+			setup:    func(s *MemoryCooldownStore) { s.Start(ctx, "key", time.Minute) },
+			wantWait: true,
+		},
+		{
+			name:  "cooldown over",
+			setup: func(s *MemoryCooldownStore) { s.expires["key"] = time.Now().Add(-time.Second) },
+		},
+		{
+			name:  "other key",
+			setup: func(s *MemoryCooldownStore) { s.Start(ctx, "other", time.Minute) },
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			store := NewMemoryCooldownStore()
+			if tc.setup != nil {
+				tc.setup(store)
+			}
+
+			wait, err := store.Start(ctx, "key", time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (wait > 0) != tc.wantWait {
+				t.Fatalf("wait = %s, want waiting = %v", wait, tc.wantWait)
+			}
+			if tc.wantWait && wait > time.Minute {
+				t.Fatalf("wait = %s, longer than the cooldown", wait)
+			}
+		})
+	}
+}
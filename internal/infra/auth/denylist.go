@@ -0,0 +1,143 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+)
+
+// TokenDenylist guarda os access tokens revogados antes de expirarem. As
+// entradas só precisam durar até a expiração natural do token.
+type TokenDenylist interface {
+	RevokeToken(ctx context.Context, tokenID string, ttl time.Duration) error
+	IsTokenRevoked(ctx context.Context, tokenID string) (bool, error)
+	// ClaimToken revoga o token só se ele ainda não estava revogado, numa
+	// única operação; retorna false se outra requisição chegou antes.
+	ClaimToken(ctx context.Context, tokenID string, ttl time.Duration) (bool, error)
+	RevokeUserTokens(ctx context.Context, userID uuid.UUID, issuedBefore time.Time, ttl time.Duration) error
+	UserTokensRevokedBefore(ctx context.Context, userID uuid.UUID) (time.Time, error)
+	RevokeSession(ctx context.Context, sessionID uuid.UUID, ttl time.Duration) error
+	IsSessionRevoked(ctx context.Context, sessionID uuid.UUID) (bool, error)
+}
+
+// RevokedByCutoff aplica o corte de RevokeUserTokens a um token. O iat só
+// tem precisão de segundos, então o token emitido no mesmo segundo do corte
+// também cai: na dúvida vale o logout, e quem entrou logo depois só precisa
+// entrar de novo. Tokens sem iat caem sempre que há um corte.
+func RevokedByCutoff(issuedAt *jwt.NumericDate, cutoff time.Time) bool {
+	if cutoff.IsZero() {
+		return false
+	}
+	if issuedAt == nil {
+		return true
+	}
+	return issuedAt.Time.Unix() <= cutoff.Unix()
+}
+
+type memoryEntry struct {
+	value     time.Time
+	expiresAt time.Time
+}
+
+// MemoryDenylist é a implementação em memória, usada quando o Redis não
+// está disponível (testes e desenvolvimento local).
+type MemoryDenylist struct {
+	mu       sync.Mutex
+	tokens   map[string]time.Time
+	users    map[uuid.UUID]memoryEntry
+	sessions map[uuid.UUID]time.Time
+}
+
+func NewMemoryDenylist() *MemoryDenylist {
+	return &MemoryDenylist{
+		tokens:   make(map[string]time.Time),
+		users:    make(map[uuid.UUID]memoryEntry),
+		sessions: make(map[uuid.UUID]time.Time),
+	}
+}
+
+func (d *MemoryDenylist) RevokeToken(ctx context.Context, tokenID string, ttl time.Duration) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.tokens[tokenID] = time.Now().Add(ttl)
+	return nil
+}
+
+func (d *MemoryDenylist) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	expiresAt, ok := d.tokens[tokenID]
+	if !ok {
+		return false, nil
+	}
+	if time.Now().After(expiresAt) {
+		delete(d.tokens, tokenID)
+		return false, nil
+	}
+	return true, nil
+}
+
+func (d *MemoryDenylist) ClaimToken(ctx context.Context, tokenID string, ttl time.Duration) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if ttl <= 0 {
+		return false, nil
+	}
+	if expiresAt, ok := d.tokens[tokenID]; ok && time.Now().Before(expiresAt) {
+		return false, nil
+	}
+	d.tokens[tokenID] = time.Now().Add(ttl)
+	return true, nil
+}
+
+func (d *MemoryDenylist) RevokeUserTokens(ctx context.Context, userID uuid.UUID, issuedBefore time.Time, ttl time.Duration) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.users[userID] = memoryEntry{value: issuedBefore, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+func (d *MemoryDenylist) UserTokensRevokedBefore(ctx context.Context, userID uuid.UUID) (time.Time, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	entry, ok := d.users[userID]
+	if !ok {
+		return time.Time{}, nil
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(d.users, userID)
+		return time.Time{}, nil
+	}
+	return entry.value, nil
+}
+
+func (d *MemoryDenylist) RevokeSession(ctx context.Context, sessionID uuid.UUID, ttl time.Duration) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.sessions[sessionID] = time.Now().Add(ttl)
+	return nil
+}
+
+func (d *MemoryDenylist) IsSessionRevoked(ctx context.Context, sessionID uuid.UUID) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	expiresAt, ok := d.sessions[sessionID]
+	if !ok {
+		return false, nil
+	}
+	if time.Now().After(expiresAt) {
+		delete(d.sessions, sessionID)
+		return false, nil
+	}
+	return true, nil
+}
@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func TestMemoryDenylistClaimToken(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		setup    func(d *MemoryDenylist)
+		ttl      time.Duration
+		expected bool
+	}{
+		{name: "first claim", ttl: time.Minute, expected: true},
+		{
+			name:     "already claimed",
+			setup:    func(d *MemoryDenylist) { d.ClaimToken(ctx, "jti", time.Minute) },
+			ttl:      time.Minute,
+			expected: false,
+		},
+		{
+			name:     "already revoked",
+			setup:    func(d *MemoryDenylist) { d.RevokeToken(ctx, "jti", time.Minute) },
+			ttl:      time.Minute,
+			expected: false,
+		},
+		{
+			name:     "previous claim expired",
+			setup:    func(d *MemoryDenylist) { d.tokens["jti"] = time.Now().Add(-time.Second) },
+			ttl:      time.Minute,
+			expected: true,
+		},
+		{name: "token already expired", ttl: 0, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewMemoryDenylist()
+			if tt.setup != nil {
+				tt.setup(d)
+			}
+
+			claimed, err := d.ClaimToken(ctx, "jti", tt.ttl)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if claimed != tt.expected {
+				t.Errorf("ClaimToken() = %v, want %v", claimed, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRevokedByCutoff(t *testing.T) {
+	cutoff := time.Unix(1_700_000_000, 500_000_000)
+
+	tests := []struct {
+		name     string
+		issuedAt *jwt.NumericDate
+		cutoff   time.Time
+		expected bool
+	}{
+		{name: "no cutoff", issuedAt: jwt.NewNumericDate(cutoff), expected: false},
+		{name: "no cutoff and no iat", expected: false},
+		{name: "no iat", cutoff: cutoff, expected: true},
+		{name: "issued a second before", issuedAt: jwt.NewNumericDate(cutoff.Add(-time.Second)), cutoff: cutoff, expected: true},
+		{name: "issued in the same second", issuedAt: jwt.NewNumericDate(cutoff), cutoff: cutoff, expected: true},
+		{name: "issued later in the same second", issuedAt: jwt.NewNumericDate(cutoff.Add(400 * time.Millisecond)), cutoff: cutoff, expected: true},
+		{name: "issued in the next second", issuedAt: jwt.NewNumericDate(cutoff.Add(500 * time.Millisecond)), cutoff: cutoff, expected: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := RevokedByCutoff(tc.issuedAt, tc.cutoff); got != tc.expected {
+				t.Fatalf("RevokedByCutoff = %v, want %v", got, tc.expected)
+			}
+		})
+	}
+}
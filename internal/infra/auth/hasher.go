@@ -0,0 +1,57 @@
+package auth
+
+// PasswordHasher é a interface comum aos algoritmos de hash de senha.
+type PasswordHasher interface {
+	HashPassword(password string) (string, string, error)
+	VerifyPassword(password, hashedPassword, salt string) bool
+	// Supports informa se o hash foi gerado por este algoritmo.
+	Supports(hashedPassword string) bool
+	// NeedsRehash indica que o hash deve ser regerado no próximo login.
+	NeedsRehash(hashedPassword string) bool
+}
+
+// MultiHasher gera hashes com o algoritmo principal e ainda valida os
+// formatos legados, detectando o formato pelo próprio hash.
+type MultiHasher struct {
+	primary PasswordHasher
+	legacy  []PasswordHasher
+}
+
+func NewMultiHasher(primary PasswordHasher, legacy ...PasswordHasher) *MultiHasher {
+	return &MultiHasher{primary: primary, legacy: legacy}
+}
+
+func (h *MultiHasher) HashPassword(password string) (string, string, error) {
+	return h.primary.HashPassword(password)
+}
+
+func (h *MultiHasher) VerifyPassword(password, hashedPassword, salt string) bool {
+	hasher := h.hasherFor(hashedPassword)
+	if hasher == nil {
+		return false
+	}
+	return hasher.VerifyPassword(password, hashedPassword, salt)
+}
+
+func (h *MultiHasher) Supports(hashedPassword string) bool {
+	return h.hasherFor(hashedPassword) != nil
+}
+
+func (h *MultiHasher) NeedsRehash(hashedPassword string) bool {
+	if !h.primary.Supports(hashedPassword) {
+		return true
+	}
+	return h.primary.NeedsRehash(hashedPassword)
+}
+
+func (h *MultiHasher) hasherFor(hashedPassword string) PasswordHasher {
+	if h.primary.Supports(hashedPassword) {
+		return h.primary
+	}
+	for _, hasher := range h.legacy {
+		if hasher.Supports(hashedPassword) {
+			return hasher
+		}
+	}
+	return nil
+}
@@ -2,6 +2,8 @@ package auth
 
 import (
 	"errors"
+	"fmt"
+	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
@@ -9,63 +11,297 @@ import (
 )
 
 type JWTConfig struct {
-	SecretKey       string
-	AccessTokenExp  time.Duration
-	RefreshTokenExp time.Duration
+	Issuer               string
+	Audience             string
+	SecretKey            string
+	SigningKeys          []*SigningKey
+	ActiveKeyID          string
+	AccessTokenExp       time.Duration
+	RefreshTokenExp      time.Duration
+	EmailVerificationExp time.Duration
+	MFAChallengeExp      time.Duration
+	ImpersonationExp     time.Duration
 }
 
+// LoadJWTConfig lê a configuração de tokens do ambiente. Com JWT_KEYS
+// (ex: "2024-01=/keys/a.pem,2024-06=/keys/b.pem") os tokens são assinados
+// com a chave JWT_ACTIVE_KID; sem ela, usa HS256 com JWT_SECRET. Os TTLs
+// aceitam o formato de time.ParseDuration (ex: "15m").
+func LoadJWTConfig() (JWTConfig, error) {
+	config := JWTConfig{
+		Issuer:               getEnv("JWT_ISSUER", "exilium-blog-backend"),
+		Audience:             getEnv("JWT_AUDIENCE", "exilium-blog-api"),
+		SecretKey:            os.Getenv("JWT_SECRET"),
+		AccessTokenExp:       15 * time.Minute,
+		RefreshTokenExp:      7 * 24 * time.Hour,
+		EmailVerificationExp: 48 * time.Hour,
+		MFAChallengeExp:      5 * time.Minute,
+		ImpersonationExp:     15 * time.Minute,
+	}
+
+	keys, err := LoadSigningKeys(os.Getenv("JWT_KEYS"))
+	if err != nil {
+		return config, err
+	}
+	config.SigningKeys = keys
+	config.ActiveKeyID = os.Getenv("JWT_ACTIVE_KID")
+
+	if len(keys) == 0 && config.SecretKey == "" {
+		return config, errors.New("either JWT_KEYS or JWT_SECRET must be set")
+	}
+	if len(keys) > 0 {
+		if config.ActiveKeyID == "" {
+			config.ActiveKeyID = keys[len(keys)-1].ID
+		}
+		active := findKey(keys, config.ActiveKeyID)
+		if active == nil || active.PrivateKey == nil {
+			return config, fmt.Errorf("active signing key %q not found or has no private key", config.ActiveKeyID)
+		}
+	}
+
+	durations := map[string]*time.Duration{
+		"JWT_ACCESS_TTL":             &config.AccessTokenExp,
+		"JWT_REFRESH_TTL":            &config.RefreshTokenExp,
+		"JWT_EMAIL_VERIFICATION_TTL": &config.EmailVerificationExp,
+		"JWT_MFA_CHALLENGE_TTL":      &config.MFAChallengeExp,
+		"JWT_IMPERSONATION_TTL":      &config.ImpersonationExp,
+	}
+	for name, target := range durations {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return config, fmt.Errorf("invalid %s: %w", name, err)
+		}
+		*target = d
+	}
+
+	return config, nil
+}
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// Tokens emitidos para um fim específico carregam a claim "purpose" e nunca
+// são aceitos como access token.
+const (
+	PurposeEmailVerification = "email_verification"
+	PurposeMFAChallenge      = "mfa_challenge"
+	PurposeOIDCState         = "oidc_state"
+)
+
+// O state do login social só precisa sobreviver à ida e volta ao provedor
+const oidcStateExp = 10 * time.Minute
+
+// JWTService é o único emissor e validador de tokens da aplicação: o
+// UserService assina com ele e o AuthMiddleware valida com ele.
 type JWTService struct {
-	config JWTConfig
+	config    JWTConfig
+	activeKey *SigningKey
 }
 
 func NewJWTService(config JWTConfig) *JWTService {
-	return &JWTService{config: config}
+	return &JWTService{
+		config:    config,
+		activeKey: findKey(config.SigningKeys, config.ActiveKeyID),
+	}
+}
+
+func findKey(keys []*SigningKey, kid string) *SigningKey {
+	for _, key := range keys {
+		if key.ID == kid {
+			return key
+		}
+	}
+	return nil
 }
 
+// Claims usa "user_id" e "role", os mesmos nomes que o AuthMiddleware
+// coloca no contexto do gin; "sub" também carrega o ID do usuário.
 type Claims struct {
-	UserID uuid.UUID `json:"user_id"`
-	Role   string    `json:"role"`
+	UserID  uuid.UUID `json:"user_id"`
+	Role    string    `json:"role,omitempty"`
+	Email   string    `json:"email,omitempty"`
+	Purpose string    `json:"purpose,omitempty"`
+	// SessionID ("sid") liga o access token à sessão que o emitiu
+	SessionID *uuid.UUID `json:"sid,omitempty"`
+	// Provider, Nonce e CodeVerifier só aparecem no state do login social
+	Provider     string `json:"provider,omitempty"`
+	Nonce        string `json:"nonce,omitempty"`
+	CodeVerifier string `json:"code_verifier,omitempty"`
+	// Actor ("act", RFC 8693) identifica o admin que age em nome do usuário
+	Actor *ActorClaim `json:"act,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func (s *JWTService) GenerateToken(userID uuid.UUID, role string) (string, error) {
-	expirationTime := time.Now().Add(s.config.AccessTokenExp)
-	claims := &Claims{
-		UserID: userID,
-		Role:   role,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-		},
+type ActorClaim struct {
+	UserID uuid.UUID `json:"sub"`
+}
+
+func (s *JWTService) AccessTokenTTL() time.Duration {
+	return s.config.AccessTokenExp
+}
+
+func (s *JWTService) GenerateToken(userID uuid.UUID, role string, sessionID uuid.UUID) (string, error) {
+	claims := s.newClaims(userID, s.config.AccessTokenExp)
+	claims.Role = role
+	claims.SessionID = &sessionID
+	claims.ID = uuid.NewString()
+
+	return s.sign(claims)
+}
+
+// GenerateImpersonationToken emite um access token curto, sem sessão nem
+// refresh token, para o admin actorID agir como userID.
+func (s *JWTService) GenerateImpersonationToken(userID uuid.UUID, role string, actorID uuid.UUID) (string, time.Time, error) {
+	claims := s.newClaims(userID, s.config.ImpersonationExp)
+	claims.Role = role
+	claims.ID = uuid.NewString()
+	claims.Actor = &ActorClaim{UserID: actorID}
+
+	token, err := s.sign(claims)
+	return token, claims.ExpiresAt.Time, err
+}
+
+// Refresh tokens são opacos: apenas o hash é persistido, o que permite
+// rotacioná-los e revogá-los no banco.
+func (s *JWTService) GenerateRefreshToken() (string, string, time.Time, error) {
+	token, tokenHash, err := GenerateOpaqueToken(32)
+	if err != nil {
+		return "", "", time.Time{}, err
 	}
+	return token, tokenHash, time.Now().Add(s.config.RefreshTokenExp), nil
+}
+
+func (s *JWTService) GenerateEmailVerificationToken(userID uuid.UUID, email string) (string, error) {
+	claims := s.newClaims(userID, s.config.EmailVerificationExp)
+	claims.Email = email
+	claims.Purpose = PurposeEmailVerification
+
+	return s.sign(claims)
+}
+
+// GenerateMFAToken emite o desafio entregue no lugar dos tokens quando o
+// usuário tem 2FA: prova que a senha foi validada, e nada além disso.
+func (s *JWTService) GenerateMFAToken(userID uuid.UUID) (string, error) {
+	claims := s.newClaims(userID, s.config.MFAChallengeExp)
+	claims.ID = uuid.NewString()
+	claims.Purpose = PurposeMFAChallenge
+
+	return s.sign(claims)
+}
+
+// GenerateOIDCStateToken assina o state, o nonce do ID token e o
+// code_verifier do PKCE, que viajam num cookie HttpOnly até o callback do
+// provedor.
+func (s *JWTService) GenerateOIDCStateToken(provider, state, nonce, codeVerifier string) (string, error) {
+	claims := s.newClaims(uuid.Nil, oidcStateExp)
+	claims.ID = state
+	claims.Purpose = PurposeOIDCState
+	claims.Provider = provider
+	claims.Nonce = nonce
+	claims.CodeVerifier = codeVerifier
+
+	return s.sign(claims)
+}
+
+func (s *JWTService) ValidateToken(tokenString string) (*Claims, error) {
+	return s.validate(tokenString, "")
+}
+
+func (s *JWTService) ValidateEmailVerificationToken(tokenString string) (*Claims, error) {
+	return s.validate(tokenString, PurposeEmailVerification)
+}
+
+func (s *JWTService) ValidateMFAToken(tokenString string) (*Claims, error) {
+	return s.validate(tokenString, PurposeMFAChallenge)
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.config.SecretKey))
+func (s *JWTService) ValidateOIDCStateToken(tokenString string) (*Claims, error) {
+	return s.validate(tokenString, PurposeOIDCState)
 }
 
-func (s *JWTService) GenerateRefreshToken(userID uuid.UUID) (string, error) {
-	expirationTime := time.Now().Add(s.config.RefreshTokenExp)
-	claims := &Claims{
+func (s *JWTService) newClaims(userID uuid.UUID, ttl time.Duration) *Claims {
+	now := time.Now()
+	return &Claims{
 		UserID: userID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
+			Issuer:    s.config.Issuer,
+			Subject:   userID.String(),
+			Audience:  jwt.ClaimStrings{s.config.Audience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
 		},
 	}
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.config.SecretKey))
+// JWKS publica as chaves públicas de todas as chaves configuradas, inclusive
+// as que já não assinam, para que outros serviços validem os tokens durante
+// a rotação. Em modo HS256 a lista fica vazia.
+func (s *JWTService) JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+	for _, key := range s.config.SigningKeys {
+		set.Keys = append(set.Keys, key.JWK())
+	}
+	return set
 }
 
-func (s *JWTService) ValidateToken(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+func (s *JWTService) sign(claims *Claims) (string, error) {
+	if s.activeKey == nil {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString([]byte(s.config.SecretKey))
+	}
+
+	token := jwt.NewWithClaims(s.activeKey.Method, claims)
+	token.Header["kid"] = s.activeKey.ID
+	return token.SignedString(s.activeKey.PrivateKey)
+}
+
+func (s *JWTService) keyFunc(token *jwt.Token) (interface{}, error) {
+	if s.activeKey == nil {
+		if token.Method.Alg() != jwt.SigningMethodHS256.Alg() {
+			return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+		}
 		return []byte(s.config.SecretKey), nil
-	})
+	}
+
+	// Com chaves assimétricas o HS256 nunca é aceito, e o algoritmo do token
+	// precisa ser o da chave indicada pelo kid
+	kid, _ := token.Header["kid"].(string)
+	key := findKey(s.config.SigningKeys, kid)
+	if key == nil {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s for key %q", token.Method.Alg(), kid)
+	}
+	return key.PublicKey, nil
+}
+
+func (s *JWTService) validate(tokenString, purpose string) (*Claims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, s.keyFunc)
 	if err != nil {
 		return nil, err
 	}
 
-	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
-		return claims, nil
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token")
 	}
 
-	return nil, errors.New("invalid token")
-}
\ No newline at end of file
+	if !claims.VerifyIssuer(s.config.Issuer, true) || !claims.VerifyAudience(s.config.Audience, true) {
+		return nil, errors.New("invalid token issuer or audience")
+	}
+
+	if claims.Purpose != purpose {
+		return nil, errors.New("invalid token")
+	}
+
+	return claims, nil
+}
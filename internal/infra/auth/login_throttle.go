@@ -0,0 +1,191 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LockoutPolicy define quantas falhas são toleradas dentro da janela e como
+// cresce o bloqueio a partir daí: BaseDelay, 2x, 4x... até MaxDelay.
+type LockoutPolicy struct {
+	FreeAttempts int64
+	BaseDelay    time.Duration
+	MaxDelay     time.Duration
+	Window       time.Duration
+}
+
+func (p LockoutPolicy) Delay(failures int64) time.Duration {
+	over := failures - p.FreeAttempts
+	if over <= 0 {
+		return 0
+	}
+	// Evita overflow no shift; a partir daqui já estamos no teto
+	if over > 30 {
+		return p.MaxDelay
+	}
+	delay := p.BaseDelay << (over - 1)
+	if delay <= 0 || delay > p.MaxDelay {
+		return p.MaxDelay
+	}
+	return delay
+}
+
+// AttemptStore guarda os contadores de falhas e os bloqueios ativos.
+type AttemptStore interface {
+	Incr(ctx context.Context, key string, window time.Duration) (int64, error)
+	Lock(ctx context.Context, key string, d time.Duration) error
+	LockedFor(ctx context.Context, key string) (time.Duration, error)
+	Reset(ctx context.Context, key string) error
+}
+
+// LoginThrottle aplica as políticas de bloqueio por conta e por IP.
+type LoginThrottle struct {
+	store   AttemptStore
+	account LockoutPolicy
+	ip      LockoutPolicy
+}
+
+func NewLoginThrottle(store AttemptStore, account, ip LockoutPolicy) *LoginThrottle {
+	return &LoginThrottle{store: store, account: account, ip: ip}
+}
+
+// NewDefaultLoginThrottle: 5 erros por conta ou 20 por IP em 1h são livres;
+// depois o bloqueio dobra a cada erro até 15min (conta) ou 1h (IP).
+func NewDefaultLoginThrottle(store AttemptStore) *LoginThrottle {
+	return NewLoginThrottle(store,
+		LockoutPolicy{FreeAttempts: 5, BaseDelay: 30 * time.Second, MaxDelay: 15 * time.Minute, Window: time.Hour},
+		LockoutPolicy{FreeAttempts: 20, BaseDelay: time.Minute, MaxDelay: time.Hour, Window: time.Hour},
+	)
+}
+
+func accountKey(account string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(account))
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+// Check retorna quanto tempo falta para a conta ou o IP poderem tentar de
+// novo; zero significa liberado.
+func (t *LoginThrottle) Check(ctx context.Context, account, ip string) (time.Duration, error) {
+	accountWait, err := t.store.LockedFor(ctx, accountKey(account))
+	if err != nil {
+		return 0, err
+	}
+	ipWait, err := t.store.LockedFor(ctx, ipKey(ip))
+	if err != nil {
+		return 0, err
+	}
+	return maxDuration(accountWait, ipWait), nil
+}
+
+// RecordFailure contabiliza a falha e retorna o bloqueio aplicado, se houver.
+func (t *LoginThrottle) RecordFailure(ctx context.Context, account, ip string) (time.Duration, error) {
+	accountWait, err := t.fail(ctx, accountKey(account), t.account)
+	if err != nil {
+		return 0, err
+	}
+	ipWait, err := t.fail(ctx, ipKey(ip), t.ip)
+	if err != nil {
+		return 0, err
+	}
+	return maxDuration(accountWait, ipWait), nil
+}
+
+// RecordSuccess zera apenas o contador da conta: um login válido não deve
+// liberar um IP que está testando várias contas.
+func (t *LoginThrottle) RecordSuccess(ctx context.Context, account string) error {
+	return t.store.Reset(ctx, accountKey(account))
+}
+
+func (t *LoginThrottle) fail(ctx context.Context, key string, policy LockoutPolicy) (time.Duration, error) {
+	failures, err := t.store.Incr(ctx, key, policy.Window)
+	if err != nil {
+		return 0, err
+	}
+
+	delay := policy.Delay(failures)
+	if delay > 0 {
+		if err := t.store.Lock(ctx, key, delay); err != nil {
+			return 0, err
+		}
+	}
+	return delay, nil
+}
+
+func maxDuration(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+type memoryCounter struct {
+	count     int64
+	expiresAt time.Time
+}
+
+// MemoryAttemptStore é a implementação em memória, para testes e para rodar
+// sem Redis.
+type MemoryAttemptStore struct {
+	mu       sync.Mutex
+	counters map[string]memoryCounter
+	locks    map[string]time.Time
+}
+
+func NewMemoryAttemptStore() *MemoryAttemptStore {
+	return &MemoryAttemptStore{
+		counters: make(map[string]memoryCounter),
+		locks:    make(map[string]time.Time),
+	}
+}
+
+func (s *MemoryAttemptStore) Incr(ctx context.Context, key string, window time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	counter, ok := s.counters[key]
+	if !ok || now.After(counter.expiresAt) {
+		counter = memoryCounter{expiresAt: now.Add(window)}
+	}
+	counter.count++
+	s.counters[key] = counter
+	return counter.count, nil
+}
+
+func (s *MemoryAttemptStore) Lock(ctx context.Context, key string, d time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.locks[key] = time.Now().Add(d)
+	return nil
+}
+
+func (s *MemoryAttemptStore) LockedFor(ctx context.Context, key string) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	until, ok := s.locks[key]
+	if !ok {
+		return 0, nil
+	}
+	remaining := time.Until(until)
+	if remaining <= 0 {
+		delete(s.locks, key)
+		return 0, nil
+	}
+	return remaining, nil
+}
+
+func (s *MemoryAttemptStore) Reset(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.counters, key)
+	delete(s.locks, key)
+	return nil
+}
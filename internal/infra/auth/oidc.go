@@ -0,0 +1,467 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// OIDCProviderConfig descreve um provedor externo de login. Com Issuer os
+// endpoints vêm do documento de discovery; AuthURL, TokenURL e UserInfoURL
+// sobrescrevem o discovery e permitem usar provedores só OAuth2 (ex: GitHub).
+type OIDCProviderConfig struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	AuthURL      string
+	TokenURL     string
+	UserInfoURL  string
+}
+
+// LoadOIDCProviders lê OIDC_PROVIDERS (ex: "google,keycloak") e, para cada
+// nome, as variáveis OIDC_<NOME>_CLIENT_ID, _CLIENT_SECRET, _REDIRECT_URL,
+// _ISSUER, _SCOPES (separados por espaço), _AUTH_URL, _TOKEN_URL e
+// _USERINFO_URL.
+func LoadOIDCProviders() ([]OIDCProviderConfig, error) {
+	var providers []OIDCProviderConfig
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		config := OIDCProviderConfig{
+			Name:         name,
+			Issuer:       strings.TrimSuffix(os.Getenv(prefix+"ISSUER"), "/"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  os.Getenv(prefix + "REDIRECT_URL"),
+			Scopes:       strings.Fields(getEnv(prefix+"SCOPES", "openid email profile")),
+			AuthURL:      os.Getenv(prefix + "AUTH_URL"),
+			TokenURL:     os.Getenv(prefix + "TOKEN_URL"),
+			UserInfoURL:  os.Getenv(prefix + "USERINFO_URL"),
+		}
+
+		if config.ClientID == "" || config.RedirectURL == "" {
+			return nil, fmt.Errorf("%sCLIENT_ID and %sREDIRECT_URL must be set", prefix, prefix)
+		}
+		if config.Issuer == "" && (config.AuthURL == "" || config.TokenURL == "" || config.UserInfoURL == "") {
+			return nil, fmt.Errorf("%sISSUER or all of %sAUTH_URL, %sTOKEN_URL and %sUSERINFO_URL must be set", prefix, prefix, prefix, prefix)
+		}
+
+		providers = append(providers, config)
+	}
+	return providers, nil
+}
+
+// OIDCUserInfo é o subconjunto das claims do userinfo usado no login.
+type OIDCUserInfo struct {
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+}
+
+// OIDCTokens é a resposta do endpoint de token. IDToken só vem de
+// provedores OIDC (escopo "openid").
+type OIDCTokens struct {
+	AccessToken string
+	IDToken     string
+}
+
+type oidcEndpoints struct {
+	Issuer      string `json:"issuer"`
+	AuthURL     string `json:"authorization_endpoint"`
+	TokenURL    string `json:"token_endpoint"`
+	UserInfoURL string `json:"userinfo_endpoint"`
+	JWKSURI     string `json:"jwks_uri"`
+}
+
+// As chaves do provedor são buscadas de novo quando aparece um kid
+// desconhecido, mas no máximo uma vez por intervalo
+const oidcKeysRefreshInterval = time.Minute
+
+// OIDCProvider implementa o fluxo authorization code + PKCE. Em provedores
+// OIDC a identidade vem do ID token, validado contra o JWKS do provedor, e o
+// userinfo só completa as claims; provedores OAuth2 que não emitem ID token
+// dependem apenas do userinfo.
+type OIDCProvider struct {
+	config     OIDCProviderConfig
+	httpClient *http.Client
+
+	mu          sync.Mutex
+	endpoints   *oidcEndpoints
+	keys        map[string]interface{}
+	keysFetched time.Time
+}
+
+func NewOIDCProvider(config OIDCProviderConfig) *OIDCProvider {
+	return &OIDCProvider{
+		config:     config,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (p *OIDCProvider) Name() string {
+	return p.config.Name
+}
+
+// AuthCodeURL monta a URL para onde o navegador é redirecionado. O nonce
+// volta dentro do ID token e prova que ele foi emitido para este login.
+func (p *OIDCProvider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	endpoints, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.config.ClientID},
+		"redirect_uri":          {p.config.RedirectURL},
+		"scope":                 {strings.Join(p.config.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {codeChallenge},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(endpoints.AuthURL, "?") {
+		separator = "&"
+	}
+	return endpoints.AuthURL + separator + params.Encode(), nil
+}
+
+// Exchange troca o código de autorização pelos tokens do provedor.
+func (p *OIDCProvider) Exchange(ctx context.Context, code, codeVerifier string) (*OIDCTokens, error) {
+	endpoints, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectURL},
+		"client_id":     {p.config.ClientID},
+		"code_verifier": {codeVerifier},
+	}
+	if p.config.ClientSecret != "" {
+		form.Set("client_secret", p.config.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoints.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		IDToken     string `json:"id_token"`
+		Error       string `json:"error"`
+	}
+	if err := p.doJSON(req, &token); err != nil {
+		return nil, fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+	if token.Error != "" {
+		return nil, fmt.Errorf("failed to exchange authorization code: %s", token.Error)
+	}
+	if token.AccessToken == "" {
+		return nil, errors.New("failed to exchange authorization code: no access token in response")
+	}
+
+	return &OIDCTokens{AccessToken: token.AccessToken, IDToken: token.IDToken}, nil
+}
+
+// Identity devolve o usuário autenticado pelos tokens. Em provedores OIDC o
+// ID token é obrigatório e precisa trazer o nonce do login; o userinfo só
+// preenche as claims que faltam e tem de ser do mesmo "sub".
+func (p *OIDCProvider) Identity(ctx context.Context, tokens *OIDCTokens, nonce string) (*OIDCUserInfo, error) {
+	info, err := p.UserInfo(ctx, tokens.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	if !p.issuesIDToken() {
+		return info, nil
+	}
+
+	identity, err := p.VerifyIDToken(ctx, tokens.IDToken, nonce)
+	if err != nil {
+		return nil, err
+	}
+	if info.Subject != identity.Subject {
+		return nil, errors.New("user info subject does not match the ID token")
+	}
+
+	if identity.Email == "" {
+		identity.Email, identity.EmailVerified = info.Email, info.EmailVerified
+	}
+	if identity.Name == "" {
+		identity.Name = info.Name
+	}
+	if identity.PreferredUsername == "" {
+		identity.PreferredUsername = info.PreferredUsername
+	}
+	return identity, nil
+}
+
+// issuesIDToken diz se o provedor é OIDC: tem issuer e o escopo "openid".
+func (p *OIDCProvider) issuesIDToken() bool {
+	if p.config.Issuer == "" {
+		return false
+	}
+	for _, scope := range p.config.Scopes {
+		if scope == "openid" {
+			return true
+		}
+	}
+	return false
+}
+
+type oidcIDTokenClaims struct {
+	Nonce             string      `json:"nonce"`
+	AuthorizedParty   string      `json:"azp"`
+	Email             string      `json:"email"`
+	EmailVerified     interface{} `json:"email_verified"`
+	Name              string      `json:"name"`
+	PreferredUsername string      `json:"preferred_username"`
+	jwt.RegisteredClaims
+}
+
+// VerifyIDToken valida o ID token: assinatura com uma chave do JWKS do
+// provedor, iss, aud (e azp, quando presente), exp e o nonce do login.
+func (p *OIDCProvider) VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (*OIDCUserInfo, error) {
+	if rawIDToken == "" {
+		return nil, errors.New("token response has no id_token")
+	}
+
+	endpoints, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Só algoritmos assimétricos: o HS256 usaria o client secret como chave
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}))
+	claims := &oidcIDTokenClaims{}
+	_, err = parser.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.signingKey(ctx, endpoints.JWKSURI, kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid id_token: %w", err)
+	}
+
+	issuer := endpoints.Issuer
+	if issuer == "" {
+		issuer = p.config.Issuer
+	}
+	if claims.Issuer != issuer {
+		return nil, errors.New("invalid id_token: unexpected issuer")
+	}
+	if !claims.VerifyAudience(p.config.ClientID, true) {
+		return nil, errors.New("invalid id_token: unexpected audience")
+	}
+	if claims.AuthorizedParty != "" && claims.AuthorizedParty != p.config.ClientID {
+		return nil, errors.New("invalid id_token: unexpected authorized party")
+	}
+	if nonce == "" || claims.Nonce != nonce {
+		return nil, errors.New("invalid id_token: nonce mismatch")
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("invalid id_token: no subject")
+	}
+
+	info := &OIDCUserInfo{
+		Subject:           claims.Subject,
+		Email:             strings.ToLower(claims.Email),
+		Name:              claims.Name,
+		PreferredUsername: claims.PreferredUsername,
+	}
+	switch verified := claims.EmailVerified.(type) {
+	case bool:
+		info.EmailVerified = verified
+	case string:
+		info.EmailVerified = verified == "true"
+	}
+	return info, nil
+}
+
+// signingKey devolve a chave pública do kid, buscando o JWKS de novo quando
+// o kid é desconhecido (o provedor pode ter rotacionado as chaves).
+func (p *OIDCProvider) signingKey(ctx context.Context, jwksURI, kid string) (interface{}, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+	if p.keys != nil && time.Since(p.keysFetched) < oidcKeysRefreshInterval {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURI, nil)
+	if err != nil {
+		return nil, err
+	}
+	var set JWKSet
+	if err := p.doJSON(req, &set); err != nil {
+		return nil, fmt.Errorf("failed to fetch signing keys of provider %s: %w", p.config.Name, err)
+	}
+
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		if key, err := jwk.PublicKey(); err == nil {
+			keys[jwk.Kid] = key
+		}
+	}
+	p.keys, p.keysFetched = keys, time.Now()
+
+	key, ok := keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key, nil
+}
+
+func (p *OIDCProvider) UserInfo(ctx context.Context, accessToken string) (*OIDCUserInfo, error) {
+	endpoints, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoints.UserInfoURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+
+	var claims map[string]interface{}
+	if err := p.doJSON(req, &claims); err != nil {
+		return nil, fmt.Errorf("failed to fetch user info: %w", err)
+	}
+
+	// "sub"/"preferred_username" são os nomes do OIDC; "id"/"login" são os
+	// equivalentes do GitHub
+	info := &OIDCUserInfo{
+		Subject:           claimString(claims, "sub", "id"),
+		Email:             strings.ToLower(claimString(claims, "email")),
+		Name:              claimString(claims, "name"),
+		PreferredUsername: claimString(claims, "preferred_username", "login"),
+	}
+	switch verified := claims["email_verified"].(type) {
+	case bool:
+		info.EmailVerified = verified
+	case string:
+		info.EmailVerified = verified == "true"
+	}
+
+	if info.Subject == "" {
+		return nil, errors.New("user info has no subject")
+	}
+	return info, nil
+}
+
+func (p *OIDCProvider) discover(ctx context.Context) (*oidcEndpoints, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.endpoints != nil {
+		return p.endpoints, nil
+	}
+
+	endpoints := &oidcEndpoints{}
+	if p.config.Issuer != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.config.Issuer+"/.well-known/openid-configuration", nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.doJSON(req, endpoints); err != nil {
+			return nil, fmt.Errorf("failed to discover provider %s: %w", p.config.Name, err)
+		}
+	}
+
+	if p.config.AuthURL != "" {
+		endpoints.AuthURL = p.config.AuthURL
+	}
+	if p.config.TokenURL != "" {
+		endpoints.TokenURL = p.config.TokenURL
+	}
+	if p.config.UserInfoURL != "" {
+		endpoints.UserInfoURL = p.config.UserInfoURL
+	}
+
+	if endpoints.AuthURL == "" || endpoints.TokenURL == "" || endpoints.UserInfoURL == "" {
+		return nil, fmt.Errorf("provider %s is missing authorization, token or userinfo endpoint", p.config.Name)
+	}
+	if p.issuesIDToken() && endpoints.JWKSURI == "" {
+		return nil, fmt.Errorf("provider %s is missing jwks_uri", p.config.Name)
+	}
+
+	p.endpoints = endpoints
+	return endpoints, nil
+}
+
+func (p *OIDCProvider) doJSON(req *http.Request, target interface{}) error {
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return json.Unmarshal(body, target)
+}
+
+func claimString(claims map[string]interface{}, names ...string) string {
+	for _, name := range names {
+		switch v := claims[name].(type) {
+		case string:
+			if v != "" {
+				return v
+			}
+		case float64:
+			return fmt.Sprintf("%.0f", v)
+		}
+	}
+	return ""
+}
+
+// GeneratePKCE gera o code_verifier e o code_challenge (S256) do RFC 7636.
+func GeneratePKCE() (string, string, error) {
+	verifier, _, err := GenerateOpaqueToken(32)
+	if err != nil {
+		return "", "", err
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	return verifier, base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
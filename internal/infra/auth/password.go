@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"crypto/subtle"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -51,23 +52,66 @@ func (s *PasswordService) HashPassword(password string) (string, string, error)
 	return encodedHash, saltStr, nil
 }
 
+// VerifyPassword usa os parâmetros e o salt gravados no próprio hash, não
+// os da configuração atual, para que hashes antigos continuem válidos depois
+// de uma mudança de parâmetros.
 func (s *PasswordService) VerifyPassword(password, encodedHash, saltStr string) bool {
-	parts := strings.Split(encodedHash, "$")
-	if len(parts) != 6 {
+	params, salt, decodedHash, err := decodeArgon2Hash(encodedHash)
+	if err != nil {
 		return false
 	}
 
-	salt, err := base64.RawStdEncoding.DecodeString(saltStr)
+	hashToCompare := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Threads, uint32(len(decodedHash)))
+
+	return subtle.ConstantTimeCompare(decodedHash, hashToCompare) == 1
+}
+
+func (s *PasswordService) Supports(encodedHash string) bool {
+	return strings.HasPrefix(encodedHash, "$argon2id$")
+}
+
+func (s *PasswordService) NeedsRehash(encodedHash string) bool {
+	params, salt, decodedHash, err := decodeArgon2Hash(encodedHash)
 	if err != nil {
-		return false
+		return true
 	}
 
-	decodedHash, err := base64.RawStdEncoding.DecodeString(parts[5])
+	return params.Time != s.config.Time ||
+		params.Memory != s.config.Memory ||
+		params.Threads != s.config.Threads ||
+		uint32(len(decodedHash)) != s.config.KeyLen ||
+		uint32(len(salt)) != s.config.SaltLen
+}
+
+func decodeArgon2Hash(encodedHash string) (PasswordConfig, []byte, []byte, error) {
+	var params PasswordConfig
+
+	parts := strings.Split(encodedHash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return params, nil, nil, errors.New("invalid argon2id hash format")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return params, nil, nil, err
+	}
+	if version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("unsupported argon2 version %d", version)
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Time, &params.Threads); err != nil {
+		return params, nil, nil, err
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
 	if err != nil {
-		return false
+		return params, nil, nil, err
 	}
 
-	hashToCompare := argon2.IDKey([]byte(password), salt, s.config.Time, s.config.Memory, s.config.Threads, uint32(len(decodedHash)))
+	decodedHash, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return params, nil, nil, err
+	}
 
-	return subtle.ConstantTimeCompare(decodedHash, hashToCompare) == 1
-}
\ No newline at end of file
+	return params, salt, decodedHash, nil
+}
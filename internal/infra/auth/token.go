@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// GenerateOpaqueToken gera um token aleatório para ser entregue ao cliente e
+// o hash que deve ser persistido no lugar dele.
+func GenerateOpaqueToken(size int) (string, string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	return token, HashToken(token), nil
+}
+
+// HashToken retorna o SHA-256 do token em hexadecimal.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// APITokenPrefix identifica os tokens de acesso pessoal, permitindo
+// diferenciá-los de um JWT sem consultar o banco.
+const APITokenPrefix = "exb_"
+
+func IsAPIToken(token string) bool {
+	return strings.HasPrefix(token, APITokenPrefix)
+}
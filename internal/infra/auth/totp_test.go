@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+// Vetores do RFC 6238 (SHA1), truncados para 6 dígitos
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestValidateTOTP(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     string
+		now      int64
+		wantStep int64
+		wantOK   bool
+	}{
+		{name: "current step", code: "287082", now: 59, wantStep: 1, wantOK: true},
+		{name: "rfc vector", code: "081804", now: 1111111109, wantStep: 37037036, wantOK: true},
+		{name: "previous step within skew", code: "081804", now: 1111111109 + totpPeriod, wantStep: 37037036, wantOK: true},
+		{name: "next step within skew", code: "050471", now: 1111111111 - totpPeriod, wantStep: 37037037, wantOK: true},
+		{name: "outside skew", code: "081804", now: 1111111109 + 2*totpPeriod, wantOK: false},
+		{name: "wrong code", code: "000000", now: 59, wantOK: false},
+		{name: "wrong length", code: "28708", now: 59, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := ValidateTOTP(rfcSecret, tt.code, time.Unix(tt.now, 0))
+			if ok != tt.wantOK {
+				t.Fatalf("ValidateTOTP() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && step != tt.wantStep {
+				t.Errorf("ValidateTOTP() step = %d, want %d", step, tt.wantStep)
+			}
+		})
+	}
+}
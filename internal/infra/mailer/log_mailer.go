@@ -0,0 +1,44 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/elaurentium/exilium-blog-backend/pkg/logger"
+)
+
+// LogMailer não envia nada: grava as mensagens como arquivos .eml em dir ou,
+// se dir estiver vazio, escreve-as no log.
+type LogMailer struct {
+	dir    string
+	from   string
+	logger *logger.Logger
+}
+
+func NewLogMailer(dir, from string) *LogMailer {
+	return &LogMailer{dir: dir, from: from, logger: logger.NewLogger()}
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	content := buildMessage(m.from, msg)
+
+	if m.dir == "" {
+		m.logger.Info("email sent\n" + string(content))
+		return nil
+	}
+
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create mail directory: %w", err)
+	}
+
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), strings.NewReplacer("@", "_at_", "/", "_").Replace(msg.To))
+	if err := os.WriteFile(filepath.Join(m.dir, name), content, 0o644); err != nil {
+		return fmt.Errorf("failed to write email to %s: %w", m.dir, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,189 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/repositories"
+)
+
+type AccountRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewAccountRepository(pool *pgxpool.Pool) repositories.AccountRepository {
+	return &AccountRepository{pool: pool}
+}
+
+func (r *AccountRepository) SoftDelete(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		UPDATE users
+		SET deleted_at = NOW(), is_active = FALSE
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	_, err := r.pool.Exec(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AccountRepository) ListDeletedBefore(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error) {
+	query := `
+		SELECT id
+		FROM users
+		WHERE deleted_at IS NOT NULL AND deleted_at < $1
+		ORDER BY deleted_at
+		LIMIT $2
+	`
+
+	rows, err := r.pool.Query(ctx, query, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deleted users: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan user ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over deleted users: %w", err)
+	}
+
+	return ids, nil
+}
+
+// Purge remove a conta de vez. Posts e comentários continuam no ar, sem
+// autor; votos, inscrições e notificações vão junto com o usuário, e os
+// contadores de votos dos alvos são descontados na mesma transação. As
+// demais tabelas ligadas a users usam ON DELETE CASCADE/SET NULL.
+func (r *AccountRepository) Purge(ctx context.Context, userID uuid.UUID) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	statements := []string{
+		`UPDATE posts SET user_id = NULL WHERE user_id = $1`,
+		`UPDATE comments SET user_id = NULL WHERE user_id = $1`,
+		`UPDATE subs SET creator_id = NULL WHERE creator_id = $1`,
+		`UPDATE posts p
+			SET upvotes = p.upvotes - v.up, downvotes = p.downvotes - v.down
+			FROM (
+				SELECT post_id,
+					COUNT(*) FILTER (WHERE type = 'upvote') AS up,
+					COUNT(*) FILTER (WHERE type = 'downvote') AS down
+				FROM votes
+				WHERE user_id = $1 AND post_id IS NOT NULL
+				GROUP BY post_id
+			) v
+			WHERE p.id = v.post_id`,
+		`UPDATE comments c
+			SET upvotes = c.upvotes - v.up, downvotes = c.downvotes - v.down
+			FROM (
+				SELECT comment_id,
+					COUNT(*) FILTER (WHERE type = 'upvote') AS up,
+					COUNT(*) FILTER (WHERE type = 'downvote') AS down
+				FROM votes
+				WHERE user_id = $1 AND comment_id IS NOT NULL
+				GROUP BY comment_id
+			) v
+			WHERE c.id = v.comment_id`,
+		`DELETE FROM votes WHERE user_id = $1`,
+		`DELETE FROM sub_members WHERE user_id = $1`,
+		`DELETE FROM user_subscriptions WHERE user_id = $1`,
+		`DELETE FROM user_notifications WHERE user_id = $1`,
+		`DELETE FROM users WHERE id = $1 AND deleted_at IS NOT NULL`,
+	}
+	for _, statement := range statements {
+		if _, err := tx.Exec(ctx, statement, userID); err != nil {
+			return fmt.Errorf("failed to purge user %s: %w", userID, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AccountRepository) ListVotes(ctx context.Context, userID uuid.UUID) ([]*entities.Vote, error) {
+	query := `
+		SELECT id, user_id, post_id, comment_id, type, created_at, updated_at
+		FROM votes
+		WHERE user_id = $1
+		ORDER BY created_at
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list votes: %w", err)
+	}
+	defer rows.Close()
+
+	var votes []*entities.Vote
+	for rows.Next() {
+		var vote entities.Vote
+		err := rows.Scan(
+			&vote.ID, &vote.UserID, &vote.PostID, &vote.CommentID, &vote.Type, &vote.CreatedAt, &vote.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan vote: %w", err)
+		}
+		votes = append(votes, &vote)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over votes: %w", err)
+	}
+
+	return votes, nil
+}
+
+func (r *AccountRepository) ListSubscriptions(ctx context.Context, userID uuid.UUID) ([]*entities.Subscription, error) {
+	query := `
+		SELECT id, user_id, sub_id, notification_preference, subscribed_at
+		FROM user_subscriptions
+		WHERE user_id = $1
+		ORDER BY subscribed_at
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var subscriptions []*entities.Subscription
+	for rows.Next() {
+		var subscription entities.Subscription
+		err := rows.Scan(
+			&subscription.ID, &subscription.UserID, &subscription.SubID, &subscription.NotificationPreference, &subscription.SubscribedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan subscription: %w", err)
+		}
+		subscriptions = append(subscriptions, &subscription)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over subscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
@@ -0,0 +1,155 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/repositories"
+)
+
+type APITokenRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewAPITokenRepository(pool *pgxpool.Pool) repositories.APITokenRepository {
+	return &APITokenRepository{pool: pool}
+}
+
+// Create conta e insere na mesma transação com a linha do usuário travada
+// (FOR UPDATE), então criações simultâneas do mesmo usuário passam uma de
+// cada vez pela contagem e o limite não é ultrapassado.
+func (r *APITokenRepository) Create(ctx context.Context, token *entities.APIToken, limit int) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var id uuid.UUID
+	err = tx.QueryRow(ctx, `SELECT id FROM users WHERE id = $1 FOR UPDATE`, token.UserID).Scan(&id)
+	if err != nil {
+		return false, fmt.Errorf("failed to lock api token owner: %w", err)
+	}
+
+	var active int
+	err = tx.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM api_tokens
+		WHERE user_id = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())
+	`, token.UserID).Scan(&active)
+	if err != nil {
+		return false, fmt.Errorf("failed to count api tokens: %w", err)
+	}
+	if active >= limit {
+		return false, nil
+	}
+
+	query := `
+		INSERT INTO api_tokens (id, user_id, name, token_prefix, token_hash, scopes, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err = tx.Exec(ctx, query,
+		token.ID, token.UserID, token.Name, token.Prefix, token.TokenHash, token.Scopes, token.ExpiresAt, token.CreatedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to create api token: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+func (r *APITokenRepository) GetByHash(ctx context.Context, tokenHash string) (*entities.APIToken, error) {
+	query := `
+		SELECT id, user_id, name, token_prefix, token_hash, scopes, expires_at, last_used_at, revoked_at, created_at
+		FROM api_tokens
+		WHERE token_hash = $1
+	`
+
+	var token entities.APIToken
+	err := r.pool.QueryRow(ctx, query, tokenHash).Scan(
+		&token.ID, &token.UserID, &token.Name, &token.Prefix, &token.TokenHash, &token.Scopes,
+		&token.ExpiresAt, &token.LastUsedAt, &token.RevokedAt, &token.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get api token: %w", err)
+	}
+
+	return &token, nil
+}
+
+func (r *APITokenRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*entities.APIToken, error) {
+	query := `
+		SELECT id, user_id, name, token_prefix, token_hash, scopes, expires_at, last_used_at, revoked_at, created_at
+		FROM api_tokens
+		WHERE user_id = $1 AND revoked_at IS NULL
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api tokens: %w", err)
+	}
+	defer rows.Close()
+
+	var tokens []*entities.APIToken
+	for rows.Next() {
+		var token entities.APIToken
+		err := rows.Scan(
+			&token.ID, &token.UserID, &token.Name, &token.Prefix, &token.TokenHash, &token.Scopes,
+			&token.ExpiresAt, &token.LastUsedAt, &token.RevokedAt, &token.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan api token: %w", err)
+		}
+		tokens = append(tokens, &token)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over api tokens: %w", err)
+	}
+
+	return tokens, nil
+}
+
+func (r *APITokenRepository) Revoke(ctx context.Context, id, userID uuid.UUID) (bool, error) {
+	query := `
+		UPDATE api_tokens
+		SET revoked_at = NOW()
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+	`
+
+	tag, err := r.pool.Exec(ctx, query, id, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke api token: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *APITokenRepository) TouchLastUsed(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE api_tokens
+		SET last_used_at = NOW()
+		WHERE id = $1
+	`
+
+	_, err := r.pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to update api token: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/repositories"
+)
+
+type AuditLogRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewAuditLogRepository(pool *pgxpool.Pool) repositories.AuditLogRepository {
+	return &AuditLogRepository{pool: pool}
+}
+
+func (r *AuditLogRepository) Create(ctx context.Context, log *entities.AuditLog) error {
+	query := `
+		INSERT INTO audit_logs (id, actor_id, target_user_id, action, details, ip, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		log.ID, log.ActorID, log.TargetUserID, log.Action, log.Details, log.IP, log.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create audit log: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AuditLogRepository) List(ctx context.Context, userID *uuid.UUID, limit, offset int) ([]*entities.AuditLog, error) {
+	query := `
+		SELECT id, actor_id, target_user_id, action, details, COALESCE(ip, ''), created_at
+		FROM audit_logs
+		WHERE $1::uuid IS NULL OR actor_id = $1 OR target_user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit logs: %w", err)
+	}
+	defer rows.Close()
+
+	var logs []*entities.AuditLog
+	for rows.Next() {
+		var log entities.AuditLog
+		err := rows.Scan(
+			&log.ID, &log.ActorID, &log.TargetUserID, &log.Action, &log.Details, &log.IP, &log.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit log: %w", err)
+		}
+		logs = append(logs, &log)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over audit logs: %w", err)
+	}
+
+	return logs, nil
+}
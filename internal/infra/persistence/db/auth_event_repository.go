@@ -0,0 +1,70 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/repositories"
+)
+
+type AuthEventRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewAuthEventRepository(pool *pgxpool.Pool) repositories.AuthEventRepository {
+	return &AuthEventRepository{pool: pool}
+}
+
+func (r *AuthEventRepository) Create(ctx context.Context, event *entities.AuthEvent) error {
+	query := `
+		INSERT INTO auth_events (id, user_id, email, ip, user_agent, type, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		event.ID, event.UserID, event.Email, event.IP, event.UserAgent, event.Type, event.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create auth event: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AuthEventRepository) List(ctx context.Context, userID *uuid.UUID, limit, offset int) ([]*entities.AuthEvent, error) {
+	query := `
+		SELECT id, user_id, COALESCE(email, ''), COALESCE(ip, ''), COALESCE(user_agent, ''), type, created_at
+		FROM auth_events
+		WHERE $1::uuid IS NULL OR user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list auth events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*entities.AuthEvent
+	for rows.Next() {
+		var event entities.AuthEvent
+		err := rows.Scan(
+			&event.ID, &event.UserID, &event.Email, &event.IP, &event.UserAgent, &event.Type, &event.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan auth event: %w", err)
+		}
+		events = append(events, &event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over auth events: %w", err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/repositories"
+)
+
+type BlockRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewBlockRepository(pool *pgxpool.Pool) repositories.BlockRepository {
+	return &BlockRepository{pool: pool}
+}
+
+// hiddenAuthorFilter esconde o conteúdo de quem o leitor (viewerParam)
+// bloqueou ou silenciou. Para visitantes anônimos o leitor é uuid.Nil e nada
+// é filtrado.
+func hiddenAuthorFilter(authorColumn, viewerParam string) string {
+	return `NOT EXISTS (SELECT 1 FROM user_blocks ub WHERE ub.blocker_id = ` + viewerParam + ` AND ub.blocked_id = ` + authorColumn + `)`
+}
+
+func (r *BlockRepository) Create(ctx context.Context, blockerID, blockedID uuid.UUID, kind string) error {
+	query := `
+		INSERT INTO user_blocks (blocker_id, blocked_id, kind, created_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (blocker_id, blocked_id, kind) DO NOTHING
+	`
+
+	_, err := r.pool.Exec(ctx, query, blockerID, blockedID, kind)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", kind, err)
+	}
+
+	return nil
+}
+
+func (r *BlockRepository) Delete(ctx context.Context, blockerID, blockedID uuid.UUID, kind string) error {
+	query := `DELETE FROM user_blocks WHERE blocker_id = $1 AND blocked_id = $2 AND kind = $3`
+
+	_, err := r.pool.Exec(ctx, query, blockerID, blockedID, kind)
+	if err != nil {
+		return fmt.Errorf("failed to delete %s: %w", kind, err)
+	}
+
+	return nil
+}
+
+func (r *BlockRepository) ListByUser(ctx context.Context, blockerID uuid.UUID) ([]*entities.Block, error) {
+	query := `
+		SELECT ub.blocked_id, u.username, ub.kind, ub.created_at
+		FROM user_blocks ub
+		JOIN users u ON u.id = ub.blocked_id
+		WHERE ub.blocker_id = $1 AND u.deleted_at IS NULL
+		ORDER BY ub.created_at DESC
+	`
+
+	rows, err := r.pool.Query(ctx, query, blockerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list blocks: %w", err)
+	}
+	defer rows.Close()
+
+	var blocks []*entities.Block
+	for rows.Next() {
+		var block entities.Block
+		if err := rows.Scan(&block.UserID, &block.Username, &block.Kind, &block.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan block: %w", err)
+		}
+		blocks = append(blocks, &block)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over blocks: %w", err)
+	}
+
+	return blocks, nil
+}
+
+func (r *BlockRepository) IsBlocked(ctx context.Context, blockerID, blockedID uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.pool.QueryRow(ctx,
+		"SELECT EXISTS(SELECT 1 FROM user_blocks WHERE blocker_id = $1 AND blocked_id = $2 AND kind = 'block')",
+		blockerID, blockedID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check block: %w", err)
+	}
+	return exists, nil
+}
@@ -22,14 +22,16 @@ func NewCommentRepository(pool *pgxpool.Pool) repositories.CommentRepository {
 
 func (r *CommentRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Comment, error) {
 	query := `
-		SELECT id, content, user_id, post_id, parent_id, upvotes, downvotes, created_at, updated_at, deleted_at
-		FROM comments
-		WHERE id = $1 AND deleted_at IS NULL
+		SELECT c.id, c.content, `+authorIDColumn("c.user_id")+`, `+authorColumn+`,
+			c.post_id, c.parent_id, c.upvotes, c.downvotes, c.created_at, c.updated_at, c.deleted_at
+		FROM comments c
+		LEFT JOIN users u ON u.id = c.user_id
+		WHERE c.id = $1 AND c.deleted_at IS NULL
 	`
 
 	var comment entities.Comment
 	err := r.pool.QueryRow(ctx, query, id).Scan(
-		&comment.ID, &comment.Content, &comment.UserID, &comment.PostID, &comment.ParentID, &comment.Upvotes, &comment.Downvotes, &comment.CreatedAt, &comment.UpdatedAt, &comment.DeletedAt,
+		&comment.ID, &comment.Content, &comment.UserID, &comment.Author, &comment.PostID, &comment.ParentID, &comment.Upvotes, &comment.Downvotes, &comment.CreatedAt, &comment.UpdatedAt, &comment.DeletedAt,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -41,16 +43,19 @@ func (r *CommentRepository) GetByID(ctx context.Context, id uuid.UUID) (*entitie
 	return &comment, nil
 }
 
-func (r *CommentRepository) GetByPost(ctx context.Context, postID uuid.UUID, limit, offset int) ([]*entities.Comment, error) {
+func (r *CommentRepository) GetByPost(ctx context.Context, postID, viewerID uuid.UUID, sort string, limit, offset int) ([]*entities.Comment, error) {
 	query := `
-		SELECT id, content, user_id, post_id, parent_id, upvotes, downvotes, created_at, updated_at
-		FROM comments
-		WHERE post_id = $1 AND deleted_at IS NULL
-		ORDER BY created_at DESC
+		SELECT c.id, c.content, `+authorIDColumn("c.user_id")+`, `+authorColumn+`,
+			c.post_id, c.parent_id, c.upvotes, c.downvotes, c.created_at, c.updated_at
+		FROM comments c
+		JOIN posts p ON p.id = c.post_id
+		LEFT JOIN users u ON u.id = c.user_id
+		WHERE c.post_id = $1 AND c.deleted_at IS NULL AND `+hiddenAuthorFilter("c.user_id", "$4")+`
+		ORDER BY `+commentOrderBy(sort)+`
 		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := r.pool.Query(ctx, query, postID, limit, offset)
+	rows, err := r.pool.Query(ctx, query, postID, limit, offset, viewerID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get comments by post: %w", err)
 	}
@@ -60,7 +65,7 @@ func (r *CommentRepository) GetByPost(ctx context.Context, postID uuid.UUID, lim
 	for rows.Next() {
 		var comment entities.Comment
 		err := rows.Scan(
-			&comment.ID, &comment.Content, &comment.UserID, &comment.PostID, &comment.ParentID, &comment.Upvotes, &comment.Downvotes, &comment.CreatedAt, &comment.UpdatedAt,
+			&comment.ID, &comment.Content, &comment.UserID, &comment.Author, &comment.PostID, &comment.ParentID, &comment.Upvotes, &comment.Downvotes, &comment.CreatedAt, &comment.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan comment: %w", err)
@@ -75,12 +80,222 @@ func (r *CommentRepository) GetByPost(ctx context.Context, postID uuid.UUID, lim
 	return comments, nil
 }
 
+// GetTree monta o trecho da árvore com uma CTE recursiva sobre parent_id.
+// ranked numera os irmãos na ordenação pedida; a recursão desce a partir dos
+// filhos de ParentID e corta por profundidade e posição. Os nós voltam por
+// nível e posição, com o total de respostas visíveis de cada um. Comentários
+// apagados que ainda têm respostas vivas ocupam seu lugar como "[deleted]" e
+// entram nas contagens (ver treeCommentFilter).
+func (r *CommentRepository) GetTree(ctx context.Context, q entities.CommentTreeQuery) ([]*entities.CommentNode, error) {
+	query := `
+		WITH RECURSIVE ranked AS (
+			SELECT c.id, c.parent_id,
+				ROW_NUMBER() OVER (PARTITION BY c.parent_id ORDER BY ` + commentOrderBy(q.Sort) + `) AS position,
+				COUNT(*) OVER (PARTITION BY c.parent_id) AS siblings
+			FROM comments c
+			JOIN posts p ON p.id = c.post_id
+			WHERE c.post_id = $1 AND ` + treeCommentFilter + ` AND ` + hiddenAuthorFilter("c.user_id", "$3") + `
+		),
+		reply_counts AS (
+			SELECT parent_id, COUNT(*) AS replies
+			FROM ranked
+			WHERE parent_id IS NOT NULL
+			GROUP BY parent_id
+		),
+		tree AS (
+			SELECT r.id, r.position, r.siblings, 1 AS depth
+			FROM ranked r
+			WHERE r.parent_id IS NOT DISTINCT FROM $2 AND r.position > $7::int AND r.position <= $7::int + $5::int
+			UNION ALL
+			SELECT r.id, r.position, r.siblings, t.depth + 1
+			FROM ranked r
+			JOIN tree t ON r.parent_id = t.id
+			WHERE t.depth < $4 AND r.position <= $6
+		)
+		SELECT ` + treeCommentColumns + `,
+			t.depth, t.position, t.siblings, COALESCE(rc.replies, 0)
+		FROM tree t
+		JOIN comments c ON c.id = t.id
+		LEFT JOIN users u ON u.id = c.user_id
+		LEFT JOIN reply_counts rc ON rc.parent_id = c.id
+		ORDER BY t.depth, t.position
+	`
+
+	rows, err := r.pool.Query(ctx, query, q.PostID, q.ParentID, q.ViewerID, q.Depth, q.Limit, q.Children, q.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment tree: %w", err)
+	}
+	defer rows.Close()
+
+	var nodes []*entities.CommentNode
+	for rows.Next() {
+		var comment entities.Comment
+		node := entities.CommentNode{Comment: &comment}
+		err := rows.Scan(
+			&comment.ID, &comment.Content, &comment.UserID, &comment.Author, &comment.PostID, &comment.ParentID, &comment.Upvotes, &comment.Downvotes, &comment.CreatedAt, &comment.UpdatedAt, &comment.DeletedAt,
+			&node.Depth, &node.Position, &node.SiblingCount, &node.ReplyCount,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan comment: %w", err)
+		}
+		nodes = append(nodes, &node)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over comment tree: %w", err)
+	}
+
+	return nodes, nil
+}
+
+// GetTreeComment busca o comentário com as regras de GetTree: apagado, ele só
+// volta (como "[deleted]") se ainda tiver respostas vivas.
+func (r *CommentRepository) GetTreeComment(ctx context.Context, id uuid.UUID) (*entities.Comment, error) {
+	query := `
+		SELECT ` + treeCommentColumns + `
+		FROM comments c
+		LEFT JOIN users u ON u.id = c.user_id
+		WHERE c.id = $1 AND ` + treeCommentFilter + `
+	`
+
+	var comment entities.Comment
+	err := r.pool.QueryRow(ctx, query, id).Scan(
+		&comment.ID, &comment.Content, &comment.UserID, &comment.Author, &comment.PostID, &comment.ParentID, &comment.Upvotes, &comment.Downvotes, &comment.CreatedAt, &comment.UpdatedAt, &comment.DeletedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get comment by ID: %w", err)
+	}
+
+	return &comment, nil
+}
+
+// GetAncestors devolve até limit ancestrais do comentário, da raiz para o
+// pai direto, com o total de respostas de cada um. Ancestrais apagados vêm
+// como "[deleted]", como em GetTree.
+func (r *CommentRepository) GetAncestors(ctx context.Context, id uuid.UUID, limit int) ([]*entities.CommentNode, error) {
+	query := `
+		WITH RECURSIVE ancestors AS (
+			SELECT c.parent_id AS id, 1 AS distance
+			FROM comments c
+			WHERE c.id = $1 AND c.parent_id IS NOT NULL
+			UNION ALL
+			SELECT c.parent_id, a.distance + 1
+			FROM ancestors a
+			JOIN comments c ON c.id = a.id
+			WHERE c.parent_id IS NOT NULL AND a.distance < $2
+		)
+		SELECT ` + treeCommentColumns + `,
+			(SELECT COUNT(*) FROM comments c WHERE c.parent_id = a.id AND ` + treeCommentFilter + `)
+		FROM ancestors a
+		JOIN comments c ON c.id = a.id
+		LEFT JOIN users u ON u.id = c.user_id
+		ORDER BY a.distance DESC
+	`
+
+	rows, err := r.pool.Query(ctx, query, id, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment ancestors: %w", err)
+	}
+	defer rows.Close()
+
+	var ancestors []*entities.CommentNode
+	for rows.Next() {
+		var comment entities.Comment
+		node := entities.CommentNode{Comment: &comment}
+		err := rows.Scan(
+			&comment.ID, &comment.Content, &comment.UserID, &comment.Author, &comment.PostID, &comment.ParentID, &comment.Upvotes, &comment.Downvotes, &comment.CreatedAt, &comment.UpdatedAt, &comment.DeletedAt,
+			&node.ReplyCount,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan comment: %w", err)
+		}
+		ancestors = append(ancestors, &node)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over comment ancestors: %w", err)
+	}
+
+	return ancestors, nil
+}
+
+// IsVisibleTo sobe a cadeia inteira de ancestrais, não só o contexto pedido
+// no permalink: em GetTree um autor oculto esconde todo o ramo abaixo dele.
+func (r *CommentRepository) IsVisibleTo(ctx context.Context, id, viewerID uuid.UUID) (bool, error) {
+	query := `
+		WITH RECURSIVE chain AS (
+			SELECT c.id, c.parent_id, c.user_id
+			FROM comments c
+			WHERE c.id = $1
+			UNION ALL
+			SELECT c.id, c.parent_id, c.user_id
+			FROM chain ch
+			JOIN comments c ON c.id = ch.parent_id
+		)
+		SELECT NOT EXISTS (SELECT 1 FROM chain c WHERE NOT ` + hiddenAuthorFilter("c.user_id", "$2") + `)
+	`
+
+	var visible bool
+	if err := r.pool.QueryRow(ctx, query, id, viewerID).Scan(&visible); err != nil {
+		return false, fmt.Errorf("failed to check comment visibility: %w", err)
+	}
+	return visible, nil
+}
+
+// treeCommentFilter mantém na árvore os comentários apagados que ainda têm
+// alguma resposta não apagada abaixo deles, em qualquer nível, para que o
+// ramo não se perca. Ramos inteiramente apagados somem.
+const treeCommentFilter = `(c.deleted_at IS NULL OR EXISTS (
+			WITH RECURSIVE descendants AS (
+				SELECT d.id, d.deleted_at FROM comments d WHERE d.parent_id = c.id
+				UNION ALL
+				SELECT d.id, d.deleted_at FROM comments d JOIN descendants ds ON d.parent_id = ds.id
+			)
+			SELECT 1 FROM descendants WHERE deleted_at IS NULL
+		))`
+
+// treeCommentColumns espera comentários como "c" e autores como "u". Nos
+// comentários apagados o conteúdo e o autor viram "[deleted]".
+var treeCommentColumns = `c.id,
+			CASE WHEN c.deleted_at IS NULL THEN c.content ELSE '[deleted]' END,
+			CASE WHEN c.deleted_at IS NULL THEN ` + authorIDColumn("c.user_id") + ` ELSE '00000000-0000-0000-0000-000000000000' END,
+			CASE WHEN c.deleted_at IS NULL THEN ` + authorColumn + ` ELSE '[deleted]' END,
+			c.post_id, c.parent_id, c.upvotes, c.downvotes, c.created_at, c.updated_at, c.deleted_at`
+
+// commentOrderBy espera comentários como "c" e o post como "p". As colunas
+// de pontuação são geradas em 017_comment_sorting.sql.
+func commentOrderBy(sort string) string {
+	switch sort {
+	case entities.CommentSortTop:
+		return "c.score DESC, c.created_at DESC, c.id"
+	case entities.CommentSortNew:
+		return "c.created_at DESC, c.id"
+	case entities.CommentSortOld:
+		return "c.created_at, c.id"
+	case entities.CommentSortControversial:
+		return "c.controversy_score DESC, c.created_at DESC, c.id"
+	case entities.CommentSortQA:
+		// Comentários do autor do post, ou respondidos por ele, sobem
+		return `(COALESCE(c.user_id = p.user_id, FALSE) OR EXISTS (
+				SELECT 1 FROM comments a
+				WHERE a.parent_id = c.id AND a.user_id = p.user_id AND a.deleted_at IS NULL
+			)) DESC, c.wilson_score DESC, c.created_at DESC, c.id`
+	default:
+		return "c.wilson_score DESC, c.created_at DESC, c.id"
+	}
+}
+
 func (r *CommentRepository) GetByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.Comment, error) {
 	query := `
-		SELECT id, content, user_id, post_id, parent_id, upvotes, downvotes, created_at, updated_at
-		FROM comments
-		WHERE user_id = $1 AND deleted_at IS NULL
-		ORDER BY created_at DESC
+		SELECT c.id, c.content, `+authorIDColumn("c.user_id")+`, `+authorColumn+`,
+			c.post_id, c.parent_id, c.upvotes, c.downvotes, c.created_at, c.updated_at
+		FROM comments c
+		LEFT JOIN users u ON u.id = c.user_id
+		WHERE c.user_id = $1 AND c.deleted_at IS NULL
+		ORDER BY c.created_at DESC
 		LIMIT $2 OFFSET $3
 	`
 
@@ -94,7 +309,7 @@ func (r *CommentRepository) GetByUser(ctx context.Context, userID uuid.UUID, lim
 	for rows.Next() {
 		var comment entities.Comment
 		err := rows.Scan(
-			&comment.ID, &comment.Content, &comment.UserID, &comment.PostID, &comment.ParentID, &comment.Upvotes, &comment.Downvotes, &comment.CreatedAt, &comment.UpdatedAt,
+			&comment.ID, &comment.Content, &comment.UserID, &comment.Author, &comment.PostID, &comment.ParentID, &comment.Upvotes, &comment.Downvotes, &comment.CreatedAt, &comment.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan comment: %w", err)
@@ -109,16 +324,19 @@ func (r *CommentRepository) GetByUser(ctx context.Context, userID uuid.UUID, lim
 	return comments, nil
 }
 
-func (r *CommentRepository) GetReplies(ctx context.Context, parentID uuid.UUID, limit, offset int) ([]*entities.Comment, error) {
+func (r *CommentRepository) GetReplies(ctx context.Context, parentID, viewerID uuid.UUID, sort string, limit, offset int) ([]*entities.Comment, error) {
 	query := `
-		SELECT id, content, user_id, post_id, parent_id, upvotes, downvotes, created_at, updated_at
-		FROM comments
-		WHERE parent_id = $1 AND deleted_at IS NULL
-		ORDER BY created_at DESC
+		SELECT c.id, c.content, `+authorIDColumn("c.user_id")+`, `+authorColumn+`,
+			c.post_id, c.parent_id, c.upvotes, c.downvotes, c.created_at, c.updated_at
+		FROM comments c
+		JOIN posts p ON p.id = c.post_id
+		LEFT JOIN users u ON u.id = c.user_id
+		WHERE c.parent_id = $1 AND c.deleted_at IS NULL AND `+hiddenAuthorFilter("c.user_id", "$4")+`
+		ORDER BY `+commentOrderBy(sort)+`
 		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := r.pool.Query(ctx, query, parentID, limit, offset)
+	rows, err := r.pool.Query(ctx, query, parentID, limit, offset, viewerID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get replies: %w", err)
 	}
@@ -128,7 +346,7 @@ func (r *CommentRepository) GetReplies(ctx context.Context, parentID uuid.UUID,
 	for rows.Next() {
 		var comment entities.Comment
 		err := rows.Scan(
-			&comment.ID, &comment.Content, &comment.UserID, &comment.PostID, &comment.ParentID, &comment.Upvotes, &comment.Downvotes, &comment.CreatedAt, &comment.UpdatedAt,
+			&comment.ID, &comment.Content, &comment.UserID, &comment.Author, &comment.PostID, &comment.ParentID, &comment.Upvotes, &comment.Downvotes, &comment.CreatedAt, &comment.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan reply: %w", err)
@@ -190,92 +408,3 @@ func (r *CommentRepository) Delete(ctx context.Context, id uuid.UUID) error {
 
 	return nil
 }
-
-func (r *CommentRepository) UpvoteComment(ctx context.Context, commentID, userID uuid.UUID) error {
-	return r.handleVote(ctx, commentID, userID, "upvote")
-}
-
-func (r *CommentRepository) DownvoteComment(ctx context.Context, commentID, userID uuid.UUID) error {
-	return r.handleVote(ctx, commentID, userID, "downvote")
-}
-
-func (r *CommentRepository) RemoveVote(ctx context.Context, commentID, userID uuid.UUID) error {
-	query := `
-		DELETE FROM votes
-		WHERE comment_id = $1 AND user_id = $2
-	`
-
-	_, err := r.pool.Exec(ctx, query, commentID, userID)
-	if err != nil {
-		return fmt.Errorf("failed to remove vote: %w", err)
-	}
-
-	return nil
-}
-
-func (r *CommentRepository) handleVote(ctx context.Context, commentID, userID uuid.UUID, voteType string) error {
-	tx, err := r.pool.Begin(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	defer tx.Rollback(ctx)
-
-	// Verifica se o usuário já votou neste comentário
-	var existingVote string
-	query := `
-		SELECT type FROM votes
-		WHERE comment_id = $1 AND user_id = $2
-	`
-	err = tx.QueryRow(ctx, query, commentID, userID).Scan(&existingVote)
-	if err != nil && err != pgx.ErrNoRows {
-		return fmt.Errorf("failed to check existing vote: %w", err)
-	}
-
-	// Atualiza o voto do usuário
-	if existingVote == voteType {
-		// Remove o voto se for o mesmo tipo
-		_, err = tx.Exec(ctx, `
-			DELETE FROM votes
-			WHERE comment_id = $1 AND user_id = $2
-		`, commentID, userID)
-	} else {
-		// Insere ou atualiza o voto
-		_, err = tx.Exec(ctx, `
-			INSERT INTO votes (id, user_id, comment_id, type, created_at, updated_at)
-			VALUES ($1, $2, $3, $4, NOW(), NOW())
-			ON CONFLICT (user_id, comment_id) DO UPDATE
-			SET type = $4, updated_at = NOW()
-		`, uuid.New(), userID, commentID, voteType)
-	}
-	if err != nil {
-		return fmt.Errorf("failed to handle vote: %w", err)
-	}
-
-	// Atualiza a contagem de votos no comentário
-	var voteUpdate string
-	if voteType == "upvote" {
-		voteUpdate = "upvotes = upvotes + 1"
-	} else {
-		voteUpdate = "downvotes = downvotes + 1"
-	}
-	if existingVote == "upvote" {
-		voteUpdate = "upvotes = upvotes - 1"
-	} else if existingVote == "downvote" {
-		voteUpdate = "downvotes = downvotes - 1"
-	}
-
-	_, err = tx.Exec(ctx, `
-		UPDATE comments
-		SET `+voteUpdate+`
-		WHERE id = $1
-	`, commentID)
-	if err != nil {
-		return fmt.Errorf("failed to update comment vote count: %w", err)
-	}
-
-	if err := tx.Commit(ctx); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
-	return nil
-}
\ No newline at end of file
@@ -2,9 +2,11 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 
+	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4/pgxpool"
 )
 
@@ -38,4 +40,19 @@ func NewPostgresPool() (*pgxpool.Pool, error) {
 	}
 
 	return pool, nil
-}
\ No newline at end of file
+}
+
+// isUniqueViolation diz se err é a violação de uma das restrições únicas
+// indicadas (código 23505 do PostgreSQL).
+func isUniqueViolation(err error, constraints ...string) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != "23505" {
+		return false
+	}
+	for _, constraint := range constraints {
+		if pgErr.ConstraintName == constraint {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/repositories"
+)
+
+type FollowRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewFollowRepository(pool *pgxpool.Pool) repositories.FollowRepository {
+	return &FollowRepository{pool: pool}
+}
+
+func (r *FollowRepository) Follow(ctx context.Context, followerID, followeeID uuid.UUID) error {
+	query := `
+		INSERT INTO follows (follower_id, followee_id, created_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (follower_id, followee_id) DO NOTHING
+	`
+
+	_, err := r.pool.Exec(ctx, query, followerID, followeeID)
+	if err != nil {
+		return fmt.Errorf("failed to follow user: %w", err)
+	}
+
+	return nil
+}
+
+func (r *FollowRepository) Unfollow(ctx context.Context, followerID, followeeID uuid.UUID) error {
+	query := `DELETE FROM follows WHERE follower_id = $1 AND followee_id = $2`
+
+	_, err := r.pool.Exec(ctx, query, followerID, followeeID)
+	if err != nil {
+		return fmt.Errorf("failed to unfollow user: %w", err)
+	}
+
+	return nil
+}
+
+func (r *FollowRepository) Counts(ctx context.Context, userID uuid.UUID) (int, int, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM follows f JOIN users u ON u.id = f.follower_id
+				WHERE f.followee_id = $1 AND u.deleted_at IS NULL),
+			(SELECT COUNT(*) FROM follows f JOIN users u ON u.id = f.followee_id
+				WHERE f.follower_id = $1 AND u.deleted_at IS NULL)
+	`
+
+	var followers, following int
+	if err := r.pool.QueryRow(ctx, query, userID).Scan(&followers, &following); err != nil {
+		return 0, 0, fmt.Errorf("failed to count follows: %w", err)
+	}
+
+	return followers, following, nil
+}
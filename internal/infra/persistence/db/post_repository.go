@@ -2,114 +2,281 @@ package db
 
 import (
 	"context"
-	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"fmt"
+
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/repositories"
 )
 
 type PostRepository struct {
 	pool *pgxpool.Pool
 }
 
-func NewPostRepository(pool *pgxpool.Pool) *PostRepository {
+func NewPostRepository(pool *pgxpool.Pool) repositories.PostRepository {
 	return &PostRepository{pool: pool}
 }
 
-func (r *PostRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Post, error) {
-	post := &entities.Post{}
-	err := r.pool.QueryRow(ctx, "SELECT id, title, content, author_id, created_at FROM posts WHERE id = $1", id).
-		Scan(&post.ID, &post.Title, &post.Content, &post.UserID, &post.CreatedAt)
-	
-	return post, err
+// postColumns espera posts como "p" e o autor como "u" (LEFT JOIN): autores
+// apagados, ou já removidos pelo purge, aparecem como "[deleted]" e com
+// user_id zerado.
+var postColumns = `p.id, p.title, p.content, ` + authorIDColumn("p.user_id") + `, ` + authorColumn + `,
+		p.sub_id, p.upvotes, p.downvotes, p.is_locked, p.is_pinned, p.suggested_sort, p.created_at, p.updated_at`
+
+const authorColumn = `COALESCE(CASE WHEN u.deleted_at IS NULL THEN u.username END, '[deleted]')`
+
+// authorIDColumn mascara o autor do mesmo jeito que authorColumn: uma conta
+// desativada não pode ser ligada ao seu conteúdo pelo id.
+func authorIDColumn(column string) string {
+	return `COALESCE(CASE WHEN u.deleted_at IS NULL THEN ` + column + ` END, '00000000-0000-0000-0000-000000000000')`
 }
 
-func (r *PostRepository) GetBySub(ctx context.Context, subredditID uuid.UUID, limit, offset int) ([]*entities.Post, error) {
-	rows, err := r.pool.Query(ctx, "SELECT id, title, content, author_id, created_at FROM posts WHERE subreddit_id = $1 LIMIT $2 OFFSET $3", subredditID, limit, offset)
+func scanPost(row pgx.Row) (*entities.Post, error) {
+	var post entities.Post
+	err := row.Scan(
+		&post.ID, &post.Title, &post.Content, &post.UserID, &post.Author, &post.SubID, &post.Upvotes, &post.Downvotes,
+		&post.IsLocked, &post.IsPinned, &post.SuggestedSort, &post.CreatedAt, &post.UpdatedAt,
+	)
 	if err != nil {
 		return nil, err
 	}
+	return &post, nil
+}
+
+func (r *PostRepository) queryPosts(ctx context.Context, query string, args ...interface{}) ([]*entities.Post, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get posts: %w", err)
+	}
 	defer rows.Close()
 
 	var posts []*entities.Post
 	for rows.Next() {
-		post := &entities.Post{}
-		if err := rows.Scan(&post.ID, &post.Title, &post.Content, &post.UserID, &post.CreatedAt); err != nil {
-			return nil, err
+		post, err := scanPost(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan post: %w", err)
 		}
 		posts = append(posts, post)
 	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over posts: %w", err)
+	}
+
 	return posts, nil
 }
 
-func (r *PostRepository) GetByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.Post, error) {
-	rows, err := r.pool.Query(ctx, "SELECT id, title, content, author_id, created_at FROM posts WHERE author_id = $1 LIMIT $2 OFFSET $3", userID, limit, offset)
+func (r *PostRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Post, error) {
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts p
+		LEFT JOIN users u ON u.id = p.user_id
+		WHERE p.id = $1 AND p.deleted_at IS NULL
+	`
+
+	post, err := scanPost(r.pool.QueryRow(ctx, query, id))
 	if err != nil {
-		return nil, err
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get post by ID: %w", err)
 	}
-	defer rows.Close()
 
-	var posts []*entities.Post
-	for rows.Next() {
-		post := &entities.Post{}
-		if err := rows.Scan(&post.ID, &post.Title, &post.Content, &post.UserID, &post.CreatedAt); err != nil {
-			return nil, err
-		}
-		posts = append(posts, post)
+	return post, nil
+}
+
+// GetBySub lista os posts do sub na ordenação pedida. Em hot, os fixados
+// vêm primeiro.
+func (r *PostRepository) GetBySub(ctx context.Context, subID, viewerID uuid.UUID, listing entities.PostListing, limit, offset int) ([]*entities.Post, error) {
+	filter, orderBy := listingClauses(listing)
+	if listing.Sort == entities.PostSortHot {
+		orderBy = "p.is_pinned DESC, " + orderBy
 	}
-	return posts, nil
+
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts p
+		LEFT JOIN users u ON u.id = p.user_id
+		WHERE p.sub_id = $1 AND p.deleted_at IS NULL AND ` + hiddenAuthorFilter("p.user_id", "$4") + filter + `
+		ORDER BY ` + orderBy + `
+		LIMIT $2 OFFSET $3
+	`
+
+	return r.queryPosts(ctx, query, subID, limit, offset, viewerID)
+}
+
+func (r *PostRepository) GetByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.Post, error) {
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts p
+		LEFT JOIN users u ON u.id = p.user_id
+		WHERE p.user_id = $1 AND p.deleted_at IS NULL
+		ORDER BY p.created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	return r.queryPosts(ctx, query, userID, limit, offset)
+}
+
+// GetFollowingFeed lista os posts de quem userID segue, do mais recente para
+// o mais antigo, a partir do cursor after (nil na primeira página).
+func (r *PostRepository) GetFollowingFeed(ctx context.Context, userID uuid.UUID, after *entities.PostCursor, limit int) ([]*entities.Post, error) {
+	query := `
+		SELECT ` + postColumns + `
+		FROM follows f
+		JOIN posts p ON p.user_id = f.followee_id
+		JOIN users u ON u.id = p.user_id
+		WHERE f.follower_id = $1 AND p.deleted_at IS NULL AND u.deleted_at IS NULL
+			AND ` + hiddenAuthorFilter("p.user_id", "$1") + `
+	`
+	args := []interface{}{userID, limit}
+	if after != nil {
+		query += ` AND (p.created_at, p.id) < ($3, $4)`
+		args = append(args, after.CreatedAt, after.ID)
+	}
+	query += `
+		ORDER BY p.created_at DESC, p.id DESC
+		LIMIT $2
+	`
+
+	return r.queryPosts(ctx, query, args...)
 }
 
 func (r *PostRepository) Create(ctx context.Context, post *entities.Post) error {
-	_, err := r.pool.Exec(ctx, "INSERT INTO posts (id, title, content, author_id, created_at) VALUES ($1, $2, $3, $4, $5)", post.ID, post.Title, post.Content, post.UserID, post.CreatedAt)
-	return err
+	query := `
+		INSERT INTO posts (id, title, content, user_id, sub_id, upvotes, downvotes, is_locked, is_pinned, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		post.ID, post.Title, post.Content, post.UserID, post.SubID, post.Upvotes, post.Downvotes,
+		post.IsLocked, post.IsPinned, post.CreatedAt, post.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create post: %w", err)
+	}
+
+	return nil
 }
 
 func (r *PostRepository) Update(ctx context.Context, post *entities.Post) error {
-	_, err := r.pool.Exec(ctx, "UPDATE posts SET title = $1, content = $2 WHERE id = $3", post.Title, post.Content, post.ID)
-	return err
+	query := `
+		UPDATE posts
+		SET title = $2, content = $3, is_locked = $4, is_pinned = $5, updated_at = $6
+		WHERE id = $1
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		post.ID, post.Title, post.Content, post.IsLocked, post.IsPinned, post.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update post: %w", err)
+	}
+
+	return nil
 }
 
-func (r *PostRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	_, err := r.pool.Exec(ctx, "DELETE FROM posts WHERE id = $1", id)
-	return err
+// SetSuggestedSort grava a ordenação padrão dos comentários; nil volta ao
+// padrão do site.
+func (r *PostRepository) SetSuggestedSort(ctx context.Context, id uuid.UUID, sort *string) error {
+	query := `
+		UPDATE posts
+		SET suggested_sort = $2
+		WHERE id = $1
+	`
+
+	_, err := r.pool.Exec(ctx, query, id, sort)
+	if err != nil {
+		return fmt.Errorf("failed to set suggested sort: %w", err)
+	}
+
+	return nil
 }
 
-func (r *PostRepository) UpvotePost(ctx context.Context, postID, userID uuid.UUID) error {
-	_, err := r.pool.Exec(ctx, "INSERT INTO post_votes (post_id, user_id, vote_type) VALUES ($1, $2, 'up') ON CONFLICT (post_id, user_id) DO UPDATE SET vote_type = 'up'", postID, userID)
-	return err
+func (r *PostRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE posts
+		SET deleted_at = NOW()
+		WHERE id = $1
+	`
+
+	_, err := r.pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete post: %w", err)
+	}
+
+	return nil
 }
 
-func (r *PostRepository) DownvotePost(ctx context.Context, postID, userID uuid.UUID) error {
-	_, err := r.pool.Exec(ctx, "INSERT INTO post_votes (post_id, user_id, vote_type) VALUES ($1, $2, 'down') ON CONFLICT (post_id, user_id) DO UPDATE SET vote_type = 'down'", postID, userID)
-	return err
+// GetFrontPage lista os posts de todos os subs na ordenação pedida.
+func (r *PostRepository) GetFrontPage(ctx context.Context, viewerID uuid.UUID, listing entities.PostListing, limit, offset int) ([]*entities.Post, error) {
+	filter, orderBy := listingClauses(listing)
+
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts p
+		LEFT JOIN users u ON u.id = p.user_id
+		WHERE p.deleted_at IS NULL AND ` + hiddenAuthorFilter("p.user_id", "$3") + filter + `
+		ORDER BY ` + orderBy + `
+		LIMIT $1 OFFSET $2
+	`
+
+	return r.queryPosts(ctx, query, limit, offset, viewerID)
 }
 
-func (r *PostRepository) RemoveVote(ctx context.Context, postID, userID uuid.UUID) error {
-	_, err := r.pool.Exec(ctx, "DELETE FROM post_votes WHERE post_id = $1 AND user_id = $2", postID, userID)
-	return err
+// windowIntervals traduz as janelas de top e controversial; "all" não filtra.
+var windowIntervals = map[string]string{
+	entities.TimeWindowHour:  "1 hour",
+	entities.TimeWindowDay:   "1 day",
+	entities.TimeWindowWeek:  "7 days",
+	entities.TimeWindowMonth: "1 month",
+	entities.TimeWindowYear:  "1 year",
 }
 
-func (r *PostRepository) GetTrending(ctx context.Context, limit int) ([]*entities.Post, error) {
-	rows, err := r.pool.Query(ctx, "SELECT id, title, content, author_id, created_at FROM posts ORDER BY score DESC LIMIT $1", limit)
-	if err != nil {
-		return nil, err
+// risingInterval limita rising aos posts recentes, ordenados pelo saldo de
+// votos por hora desde a publicação.
+const risingInterval = "1 day"
+
+// listingClauses monta o filtro extra e o ORDER BY de cada ordenação. hot,
+// top e controversial usam as colunas geradas de 016_post_ranking.sql, que
+// são indexadas; rising depende da hora atual e é calculado na consulta, só
+// sobre os posts do último dia.
+func listingClauses(listing entities.PostListing) (string, string) {
+	switch listing.Sort {
+	case entities.PostSortNew:
+		return "", "p.created_at DESC, p.id DESC"
+	case entities.PostSortTop:
+		return windowFilter(listing.Window), "p.score DESC, p.created_at DESC, p.id DESC"
+	case entities.PostSortControversial:
+		return windowFilter(listing.Window), "p.controversy_score DESC, p.created_at DESC, p.id DESC"
+	case entities.PostSortRising:
+		return windowFilterInterval(risingInterval),
+			"p.score / (EXTRACT(EPOCH FROM NOW() - p.created_at)::DOUBLE PRECISION / 3600 + 2) DESC, p.id DESC"
+	default:
+		return "", "p.hot_score DESC, p.id DESC"
 	}
-	defer rows.Close()
+}
 
-	var posts []*entities.Post
-	for rows.Next() {
-		post := &entities.Post{}
-		if err := rows.Scan(&post.ID, &post.Title, &post.Content, &post.UserID, &post.CreatedAt); err != nil {
-			return nil, err
-		}
-		posts = append(posts, post)
+func windowFilter(window string) string {
+	interval, ok := windowIntervals[window]
+	if !ok {
+		return ""
 	}
-	return posts, nil
+	return windowFilterInterval(interval)
+}
+
+func windowFilterInterval(interval string) string {
+	return " AND p.created_at >= NOW() - INTERVAL '" + interval + "'"
 }
 
 func (r *PostRepository) GetCommentCount(ctx context.Context, postID uuid.UUID) (int, error) {
 	var count int
-	err := r.pool.QueryRow(ctx, "SELECT COUNT(*) FROM comments WHERE post_id = $1", postID).Scan(&count)
-	return count, err
+	err := r.pool.QueryRow(ctx, "SELECT COUNT(*) FROM comments WHERE post_id = $1 AND deleted_at IS NULL", postID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count comments: %w", err)
+	}
+	return count, nil
 }
-
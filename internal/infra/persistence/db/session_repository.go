@@ -0,0 +1,136 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/repositories"
+)
+
+type SessionRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewSessionRepository(pool *pgxpool.Pool) repositories.SessionRepository {
+	return &SessionRepository{pool: pool}
+}
+
+func (r *SessionRepository) Create(ctx context.Context, session *entities.Session) error {
+	query := `
+		INSERT INTO sessions (id, user_id, user_agent, ip, created_at, last_seen_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		session.ID, session.UserID, session.UserAgent, session.IP, session.CreatedAt, session.LastSeenAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SessionRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Session, error) {
+	query := `
+		SELECT id, user_id, COALESCE(user_agent, ''), COALESCE(ip, ''), created_at, last_seen_at, revoked_at
+		FROM sessions
+		WHERE id = $1
+	`
+
+	var session entities.Session
+	err := r.pool.QueryRow(ctx, query, id).Scan(
+		&session.ID, &session.UserID, &session.UserAgent, &session.IP, &session.CreatedAt, &session.LastSeenAt, &session.RevokedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	return &session, nil
+}
+
+func (r *SessionRepository) ListActiveByUser(ctx context.Context, userID uuid.UUID) ([]*entities.Session, error) {
+	query := `
+		SELECT id, user_id, COALESCE(user_agent, ''), COALESCE(ip, ''), created_at, last_seen_at, revoked_at
+		FROM sessions
+		WHERE user_id = $1 AND revoked_at IS NULL
+		ORDER BY last_seen_at DESC
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []*entities.Session
+	for rows.Next() {
+		var session entities.Session
+		err := rows.Scan(
+			&session.ID, &session.UserID, &session.UserAgent, &session.IP, &session.CreatedAt, &session.LastSeenAt, &session.RevokedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		sessions = append(sessions, &session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+func (r *SessionRepository) Touch(ctx context.Context, id uuid.UUID, ip, userAgent string) error {
+	query := `
+		UPDATE sessions
+		SET last_seen_at = NOW(), ip = $2, user_agent = $3
+		WHERE id = $1
+	`
+
+	_, err := r.pool.Exec(ctx, query, id, ip, userAgent)
+	if err != nil {
+		return fmt.Errorf("failed to update session: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SessionRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE sessions
+		SET revoked_at = NOW()
+		WHERE id = $1 AND revoked_at IS NULL
+	`
+
+	_, err := r.pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SessionRepository) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		UPDATE sessions
+		SET revoked_at = NOW()
+		WHERE user_id = $1 AND revoked_at IS NULL
+	`
+
+	_, err := r.pool.Exec(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	return nil
+}
@@ -22,14 +22,14 @@ func NewSubRepository(pool *pgxpool.Pool) repositories.SubRepository {
 
 func (r *SubRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Sub, error) {
 	query := `
-		SELECT id, name, description, rules, creator_id, is_private, banner_url, icon_url, created_at, updated_at, deleted_at
+		SELECT id, name, description, rules, COALESCE(creator_id, '00000000-0000-0000-0000-000000000000'), is_private, banner_url, icon_url, min_karma, created_at, updated_at, deleted_at
 		FROM subs
 		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	var sub entities.Sub
 	err := r.pool.QueryRow(ctx, query, id).Scan(
-		&sub.ID, &sub.Name, &sub.Description, &sub.Rules, &sub.CreatorID, &sub.IsPrivate, &sub.BannerURL, &sub.IconURL, &sub.CreatedAt, &sub.UpdatedAt, &sub.DeletedAt,
+		&sub.ID, &sub.Name, &sub.Description, &sub.Rules, &sub.CreatorID, &sub.IsPrivate, &sub.BannerURL, &sub.IconURL, &sub.MinKarma, &sub.CreatedAt, &sub.UpdatedAt, &sub.DeletedAt,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -43,14 +43,14 @@ func (r *SubRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Su
 
 func (r *SubRepository) GetByName(ctx context.Context, name string) (*entities.Sub, error) {
 	query := `
-		SELECT id, name, description, rules, creator_id, is_private, banner_url, icon_url, created_at, updated_at, deleted_at
+		SELECT id, name, description, rules, COALESCE(creator_id, '00000000-0000-0000-0000-000000000000'), is_private, banner_url, icon_url, min_karma, created_at, updated_at, deleted_at
 		FROM subs
 		WHERE name = $1 AND deleted_at IS NULL
 	`
 
 	var sub entities.Sub
 	err := r.pool.QueryRow(ctx, query, name).Scan(
-		&sub.ID, &sub.Name, &sub.Description, &sub.Rules, &sub.CreatorID, &sub.IsPrivate, &sub.BannerURL, &sub.IconURL, &sub.CreatedAt, &sub.UpdatedAt, &sub.DeletedAt,
+		&sub.ID, &sub.Name, &sub.Description, &sub.Rules, &sub.CreatorID, &sub.IsPrivate, &sub.BannerURL, &sub.IconURL, &sub.MinKarma, &sub.CreatedAt, &sub.UpdatedAt, &sub.DeletedAt,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -64,12 +64,12 @@ func (r *SubRepository) GetByName(ctx context.Context, name string) (*entities.S
 
 func (r *SubRepository) Create(ctx context.Context, sub *entities.Sub) error {
 	query := `
-		INSERT INTO subs (id, name, description, rules, creator_id, is_private, banner_url, icon_url, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		INSERT INTO subs (id, name, description, rules, creator_id, is_private, banner_url, icon_url, min_karma, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 	`
 
 	_, err := r.pool.Exec(ctx, query,
-		sub.ID, sub.Name, sub.Description, sub.Rules, sub.CreatorID, sub.IsPrivate, sub.BannerURL, sub.IconURL, sub.CreatedAt, sub.UpdatedAt,
+		sub.ID, sub.Name, sub.Description, sub.Rules, sub.CreatorID, sub.IsPrivate, sub.BannerURL, sub.IconURL, sub.MinKarma, sub.CreatedAt, sub.UpdatedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create sub: %w", err)
@@ -81,12 +81,12 @@ func (r *SubRepository) Create(ctx context.Context, sub *entities.Sub) error {
 func (r *SubRepository) Update(ctx context.Context, sub *entities.Sub) error {
 	query := `
 		UPDATE subs
-		SET name = $2, description = $3, rules = $4, is_private = $5, banner_url = $6, icon_url = $7, updated_at = $8
+		SET name = $2, description = $3, rules = $4, is_private = $5, banner_url = $6, icon_url = $7, min_karma = $8, updated_at = $9
 		WHERE id = $1
 	`
 
 	_, err := r.pool.Exec(ctx, query,
-		sub.ID, sub.Name, sub.Description, sub.Rules, sub.IsPrivate, sub.BannerURL, sub.IconURL, sub.UpdatedAt,
+		sub.ID, sub.Name, sub.Description, sub.Rules, sub.IsPrivate, sub.BannerURL, sub.IconURL, sub.MinKarma, sub.UpdatedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update sub: %w", err)
@@ -112,7 +112,7 @@ func (r *SubRepository) Delete(ctx context.Context, id uuid.UUID) error {
 
 func (r *SubRepository) List(ctx context.Context, limit, offset int) ([]*entities.Sub, error) {
 	query := `
-		SELECT id, name, description, rules, creator_id, is_private, banner_url, icon_url, created_at, updated_at
+		SELECT id, name, description, rules, COALESCE(creator_id, '00000000-0000-0000-0000-000000000000'), is_private, banner_url, icon_url, min_karma, created_at, updated_at
 		FROM subs
 		WHERE deleted_at IS NULL
 		LIMIT $1 OFFSET $2
@@ -128,7 +128,7 @@ func (r *SubRepository) List(ctx context.Context, limit, offset int) ([]*entitie
 	for rows.Next() {
 		var sub entities.Sub
 		err := rows.Scan(
-			&sub.ID, &sub.Name, &sub.Description, &sub.Rules, &sub.CreatorID, &sub.IsPrivate, &sub.BannerURL, &sub.IconURL, &sub.CreatedAt, &sub.UpdatedAt,
+			&sub.ID, &sub.Name, &sub.Description, &sub.Rules, &sub.CreatorID, &sub.IsPrivate, &sub.BannerURL, &sub.IconURL, &sub.MinKarma, &sub.CreatedAt, &sub.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan sub: %w", err)
@@ -145,7 +145,7 @@ func (r *SubRepository) List(ctx context.Context, limit, offset int) ([]*entitie
 
 func (r *SubRepository) GetTrending(ctx context.Context, limit int) ([]*entities.Sub, error) {
 	query := `
-		SELECT s.id, s.name, s.description, s.rules, s.creator_id, s.is_private, s.banner_url, s.icon_url, s.created_at, s.updated_at
+		SELECT s.id, s.name, s.description, s.rules, COALESCE(s.creator_id, '00000000-0000-0000-0000-000000000000'), s.is_private, s.banner_url, s.icon_url, s.min_karma, s.created_at, s.updated_at
 		FROM subs s
 		LEFT JOIN posts p ON s.id = p.sub_id
 		WHERE s.deleted_at IS NULL
@@ -164,7 +164,7 @@ func (r *SubRepository) GetTrending(ctx context.Context, limit int) ([]*entities
 	for rows.Next() {
 		var sub entities.Sub
 		err := rows.Scan(
-			&sub.ID, &sub.Name, &sub.Description, &sub.Rules, &sub.CreatorID, &sub.IsPrivate, &sub.BannerURL, &sub.IconURL, &sub.CreatedAt, &sub.UpdatedAt,
+			&sub.ID, &sub.Name, &sub.Description, &sub.Rules, &sub.CreatorID, &sub.IsPrivate, &sub.BannerURL, &sub.IconURL, &sub.MinKarma, &sub.CreatedAt, &sub.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan sub: %w", err)
@@ -177,4 +177,60 @@ func (r *SubRepository) GetTrending(ctx context.Context, limit int) ([]*entities
 	}
 
 	return subs, nil
-}
\ No newline at end of file
+}
+
+// IsModerator considera moderador quem criou o sub ou tem o papel moderator
+// ou admin em sub_members.
+func (r *SubRepository) IsModerator(ctx context.Context, subID, userID uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM subs WHERE id = $1 AND creator_id = $2
+		) OR EXISTS (
+			SELECT 1 FROM sub_members
+			WHERE sub_id = $1 AND user_id = $2 AND role IN ('moderator', 'admin')
+		)
+	`
+
+	var isModerator bool
+	if err := r.pool.QueryRow(ctx, query, subID, userID).Scan(&isModerator); err != nil {
+		return false, fmt.Errorf("failed to check sub moderator: %w", err)
+	}
+
+	return isModerator, nil
+}
+
+func (r *SubRepository) Ban(ctx context.Context, ban *entities.SubBan) error {
+	query := `
+		INSERT INTO sub_bans (sub_id, user_id, banned_by, reason, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (sub_id, user_id) DO UPDATE
+		SET banned_by = EXCLUDED.banned_by, reason = EXCLUDED.reason
+	`
+
+	_, err := r.pool.Exec(ctx, query, ban.SubID, ban.UserID, ban.BannedBy, ban.Reason, ban.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to ban user from sub: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SubRepository) Unban(ctx context.Context, subID, userID uuid.UUID) (bool, error) {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM sub_bans WHERE sub_id = $1 AND user_id = $2`, subID, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to unban user from sub: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *SubRepository) IsBanned(ctx context.Context, subID, userID uuid.UUID) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM sub_bans WHERE sub_id = $1 AND user_id = $2)`
+
+	var banned bool
+	if err := r.pool.QueryRow(ctx, query, subID, userID).Scan(&banned); err != nil {
+		return false, fmt.Errorf("failed to check sub ban: %w", err)
+	}
+
+	return banned, nil
+}
@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/repositories"
+)
+
+type UserIdentityRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewUserIdentityRepository(pool *pgxpool.Pool) repositories.UserIdentityRepository {
+	return &UserIdentityRepository{pool: pool}
+}
+
+func (r *UserIdentityRepository) Create(ctx context.Context, identity *entities.UserIdentity) error {
+	query := `
+		INSERT INTO user_identities (id, user_id, provider, subject, email, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		identity.ID, identity.UserID, identity.Provider, identity.Subject, identity.Email, identity.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create user identity: %w", err)
+	}
+
+	return nil
+}
+
+func (r *UserIdentityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (*entities.UserIdentity, error) {
+	query := `
+		SELECT id, user_id, provider, subject, COALESCE(email, ''), created_at
+		FROM user_identities
+		WHERE provider = $1 AND subject = $2
+	`
+
+	var identity entities.UserIdentity
+	err := r.pool.QueryRow(ctx, query, provider, subject).Scan(
+		&identity.ID, &identity.UserID, &identity.Provider, &identity.Subject, &identity.Email, &identity.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get user identity: %w", err)
+	}
+
+	return &identity, nil
+}
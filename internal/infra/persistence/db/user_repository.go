@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/repositories"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v4/pgxpool"
 )
@@ -13,11 +14,17 @@ type UserRepository interface {
 	Create(ctx context.Context, user *entities.User) error
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.User, error)
 	GetByEmail(ctx context.Context, email string) (*entities.User, error)
+	GetByUsername(ctx context.Context, username string) (*entities.User, error)
 	Update(ctx context.Context, user *entities.User) error
+	UseTOTPStep(ctx context.Context, id uuid.UUID, step int64) (bool, error)
 	CheckEmailExists(ctx context.Context, email string) (bool, error)
 	CheckUsernameExists(ctx context.Context, username string) (bool, error)
 }
 
+// Restrições únicas sobre o nome: a original, sensível a maiúsculas, e o
+// índice sobre LOWER(username)
+var usernameConstraints = []string{"users_username_key", "idx_users_username_lower"}
+
 type userRepository struct {
 	pool *pgxpool.Pool
 }
@@ -33,17 +40,22 @@ func (r *userRepository) Create(ctx context.Context, user *entities.User) error
 	_, err := r.pool.Exec(ctx, query,
 		user.ID, user.Username, user.Email, user.HashedPassword, user.Salt, user.Birthday, user.FullName,
 		user.Bio, user.AvatarURL, user.Role, user.IsActive, user.EmailVerified, user.CreatedAt, user.UpdatedAt)
+	if isUniqueViolation(err, usernameConstraints...) {
+		return repositories.ErrUsernameTaken
+	}
 	return err
 }
 
 func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.User, error) {
 	user := &entities.User{}
 	query := `
-		SELECT id, username, email, hashed_password, salt, full_name, bio, avatar_url, role, is_active, email_verified, last_login, created_at, updated_at
+		SELECT id, username, email, hashed_password, salt, full_name, bio, avatar_url, role, is_active, email_verified,
+			COALESCE(totp_secret, ''), totp_enabled, post_karma, comment_karma, last_login, created_at, updated_at
 		FROM users WHERE id = $1 AND deleted_at IS NULL`
 	err := r.pool.QueryRow(ctx, query, id).Scan(
 		&user.ID, &user.Username, &user.Email, &user.HashedPassword, &user.Salt, &user.FullName,
-		&user.Bio, &user.AvatarURL, &user.Role, &user.IsActive, &user.EmailVerified, &user.LastLogin, &user.CreatedAt, &user.UpdatedAt)
+		&user.Bio, &user.AvatarURL, &user.Role, &user.IsActive, &user.EmailVerified,
+		&user.TOTPSecret, &user.TOTPEnabled, &user.PostKarma, &user.CommentKarma, &user.LastLogin, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
 		return nil, err // Handle sql.ErrNoRows as needed
 	}
@@ -53,28 +65,60 @@ func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.U
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*entities.User, error) {
 	user := &entities.User{}
 	query := `
-		SELECT id, username, email, hashed_password, salt, full_name, bio, avatar_url, role, is_active, email_verified, last_login, created_at, updated_at
+		SELECT id, username, email, hashed_password, salt, full_name, bio, avatar_url, role, is_active, email_verified,
+			COALESCE(totp_secret, ''), totp_enabled, post_karma, comment_karma, last_login, created_at, updated_at
 		FROM users WHERE email = $1 AND deleted_at IS NULL`
 	err := r.pool.QueryRow(ctx, query, email).Scan(
 		&user.ID, &user.Username, &user.Email, &user.HashedPassword, &user.Salt, &user.FullName,
-		&user.Bio, &user.AvatarURL, &user.Role, &user.IsActive, &user.EmailVerified, &user.LastLogin, &user.CreatedAt, &user.UpdatedAt)
+		&user.Bio, &user.AvatarURL, &user.Role, &user.IsActive, &user.EmailVerified,
+		&user.TOTPSecret, &user.TOTPEnabled, &user.PostKarma, &user.CommentKarma, &user.LastLogin, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
 		return nil, err // Handle sql.ErrNoRows as needed
 	}
 	return user, nil
 }
 
+func (r *userRepository) GetByUsername(ctx context.Context, username string) (*entities.User, error) {
+	user := &entities.User{}
+	query := `
+		SELECT id, username, email, hashed_password, salt, full_name, bio, avatar_url, role, is_active, email_verified,
+			COALESCE(totp_secret, ''), totp_enabled, post_karma, comment_karma, last_login, created_at, updated_at
+		FROM users WHERE LOWER(username) = LOWER($1) AND deleted_at IS NULL`
+	err := r.pool.QueryRow(ctx, query, username).Scan(
+		&user.ID, &user.Username, &user.Email, &user.HashedPassword, &user.Salt, &user.FullName,
+		&user.Bio, &user.AvatarURL, &user.Role, &user.IsActive, &user.EmailVerified,
+		&user.TOTPSecret, &user.TOTPEnabled, &user.PostKarma, &user.CommentKarma, &user.LastLogin, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
 func (r *userRepository) Update(ctx context.Context, user *entities.User) error {
 	query := `
 		UPDATE users SET username = $2, email = $3, hashed_password = $4, salt = $5, full_name = $6, bio = $7,
-			avatar_url = $8, role = $9, is_active = $10, email_verified = $11, last_login = $12, updated_at = $13
+			avatar_url = $8, role = $9, is_active = $10, email_verified = $11, last_login = $12, updated_at = $13,
+			totp_secret = NULLIF($14, ''), totp_enabled = $15
 		WHERE id = $1 AND deleted_at IS NULL`
 	_, err := r.pool.Exec(ctx, query,
 		user.ID, user.Username, user.Email, user.HashedPassword, user.Salt, user.FullName,
-		user.Bio, user.AvatarURL, user.Role, user.IsActive, user.EmailVerified, user.LastLogin, user.UpdatedAt)
+		user.Bio, user.AvatarURL, user.Role, user.IsActive, user.EmailVerified, user.LastLogin, user.UpdatedAt,
+		user.TOTPSecret, user.TOTPEnabled)
 	return err
 }
 
+// UseTOTPStep grava o passo TOTP aceito só se ele for posterior ao último
+// usado, numa única instrução; false indica um código repetido.
+func (r *userRepository) UseTOTPStep(ctx context.Context, id uuid.UUID, step int64) (bool, error) {
+	tag, err := r.pool.Exec(ctx,
+		"UPDATE users SET totp_last_step = $2 WHERE id = $1 AND (totp_last_step IS NULL OR totp_last_step < $2)",
+		id, step)
+	if err != nil {
+		return false, fmt.Errorf("failed to record totp step: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
 func (r *userRepository) CheckEmailExists(ctx context.Context, email string) (bool, error) {
 	var exists bool
 	err := r.pool.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM users WHERE email = $1 AND deleted_at IS NULL)", email).Scan(&exists)
@@ -86,7 +130,7 @@ func (r *userRepository) CheckEmailExists(ctx context.Context, email string) (bo
 
 func (r *userRepository) CheckUsernameExists(ctx context.Context, username string) (bool, error) {
 	var exists bool
-	err := r.pool.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM users WHERE username = $1 AND deleted_at IS NULL)", username).Scan(&exists)
+	err := r.pool.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM users WHERE LOWER(username) = LOWER($1) AND deleted_at IS NULL)", username).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("failed to check username existence: %w", err)
 	}
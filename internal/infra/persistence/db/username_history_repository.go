@@ -0,0 +1,100 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/repositories"
+)
+
+type UsernameHistoryRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewUsernameHistoryRepository(pool *pgxpool.Pool) repositories.UsernameHistoryRepository {
+	return &UsernameHistoryRepository{pool: pool}
+}
+
+func (r *UsernameHistoryRepository) ChangeUsername(ctx context.Context, change *entities.UsernameChange) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// A condição no nome antigo evita que duas trocas simultâneas passem
+	tag, err := tx.Exec(ctx, `
+		UPDATE users
+		SET username = $3, updated_at = $4
+		WHERE id = $1 AND username = $2 AND deleted_at IS NULL
+	`, change.UserID, change.OldUsername, change.NewUsername, change.ChangedAt)
+	if isUniqueViolation(err, usernameConstraints...) {
+		return repositories.ErrUsernameTaken
+	}
+	if err != nil {
+		return fmt.Errorf("failed to change username: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("username was changed concurrently")
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO username_history (id, user_id, old_username, new_username, changed_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, change.ID, change.UserID, change.OldUsername, change.NewUsername, change.ChangedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record username change: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *UsernameHistoryRepository) GetLastByUser(ctx context.Context, userID uuid.UUID) (*entities.UsernameChange, error) {
+	query := `
+		SELECT id, user_id, old_username, new_username, changed_at
+		FROM username_history
+		WHERE user_id = $1
+		ORDER BY changed_at DESC
+		LIMIT 1
+	`
+
+	return r.queryOne(ctx, query, userID)
+}
+
+func (r *UsernameHistoryRepository) GetRecentByOldUsername(ctx context.Context, username string, since time.Time) (*entities.UsernameChange, error) {
+	query := `
+		SELECT id, user_id, old_username, new_username, changed_at
+		FROM username_history
+		WHERE LOWER(old_username) = LOWER($1) AND changed_at > $2
+		ORDER BY changed_at DESC
+		LIMIT 1
+	`
+
+	return r.queryOne(ctx, query, username, since)
+}
+
+func (r *UsernameHistoryRepository) queryOne(ctx context.Context, query string, args ...interface{}) (*entities.UsernameChange, error) {
+	var change entities.UsernameChange
+	err := r.pool.QueryRow(ctx, query, args...).Scan(
+		&change.ID, &change.UserID, &change.OldUsername, &change.NewUsername, &change.ChangedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get username change: %w", err)
+	}
+
+	return &change, nil
+}
@@ -0,0 +1,168 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/repositories"
+)
+
+type VoteRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewVoteRepository(pool *pgxpool.Pool) repositories.VoteRepository {
+	return &VoteRepository{pool: pool}
+}
+
+func (r *VoteRepository) SetPostVote(ctx context.Context, postID, userID uuid.UUID, dir int) (bool, error) {
+	return r.setVote(ctx, "posts", "post_id", postID, userID, dir)
+}
+
+func (r *VoteRepository) SetCommentVote(ctx context.Context, commentID, userID uuid.UUID, dir int) (bool, error) {
+	return r.setVote(ctx, "comments", "comment_id", commentID, userID, dir)
+}
+
+// setVote grava o voto e ajusta os contadores do alvo na mesma transação. A
+// linha do alvo fica travada (FOR UPDATE) até o commit, então votos
+// simultâneos no mesmo post ou comentário são aplicados um de cada vez e o
+// voto anterior lido aqui é sempre o atual.
+func (r *VoteRepository) setVote(ctx context.Context, table, column string, targetID, userID uuid.UUID, dir int) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var id uuid.UUID
+	err = tx.QueryRow(ctx, `SELECT id FROM `+table+` WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, targetID).Scan(&id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to lock vote target: %w", err)
+	}
+
+	var current string
+	err = tx.QueryRow(ctx, `SELECT type FROM votes WHERE user_id = $1 AND `+column+` = $2`, userID, targetID).Scan(&current)
+	if err != nil && err != pgx.ErrNoRows {
+		return false, fmt.Errorf("failed to check existing vote: %w", err)
+	}
+
+	previous := entities.VoteDirection(current)
+	if previous == dir {
+		return true, nil
+	}
+
+	if dir == 0 {
+		_, err = tx.Exec(ctx, `DELETE FROM votes WHERE user_id = $1 AND `+column+` = $2`, userID, targetID)
+	} else {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO votes (id, user_id, `+column+`, type, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, NOW(), NOW())
+			ON CONFLICT (user_id, `+column+`) WHERE `+column+` IS NOT NULL DO UPDATE
+			SET type = EXCLUDED.type, updated_at = NOW()
+		`, uuid.New(), userID, targetID, voteType(dir))
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to save vote: %w", err)
+	}
+
+	upvotes, downvotes := voteCounts(dir)
+	oldUpvotes, oldDownvotes := voteCounts(previous)
+	_, err = tx.Exec(ctx, `
+		UPDATE `+table+`
+		SET upvotes = upvotes + $2, downvotes = downvotes + $3
+		WHERE id = $1
+	`, targetID, upvotes-oldUpvotes, downvotes-oldDownvotes)
+	if err != nil {
+		return false, fmt.Errorf("failed to update vote counts: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+func voteType(dir int) string {
+	if dir > 0 {
+		return entities.VoteTypeUp
+	}
+	return entities.VoteTypeDown
+}
+
+// voteCounts diz quanto um voto na direção dir soma em upvotes e downvotes.
+func voteCounts(dir int) (int, int) {
+	switch {
+	case dir > 0:
+		return 1, 0
+	case dir < 0:
+		return 0, 1
+	}
+	return 0, 0
+}
+
+func (r *VoteRepository) GetPostVotes(ctx context.Context, userID uuid.UUID, postIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	query := `
+		SELECT post_id, type
+		FROM votes
+		WHERE user_id = $1 AND post_id = ANY($2::uuid[])
+	`
+
+	return r.queryVotes(ctx, query, userID, postIDs)
+}
+
+func (r *VoteRepository) GetCommentVotes(ctx context.Context, userID uuid.UUID, commentIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	query := `
+		SELECT comment_id, type
+		FROM votes
+		WHERE user_id = $1 AND comment_id = ANY($2::uuid[])
+	`
+
+	return r.queryVotes(ctx, query, userID, commentIDs)
+}
+
+func (r *VoteRepository) queryVotes(ctx context.Context, query string, userID uuid.UUID, ids []uuid.UUID) (map[uuid.UUID]int, error) {
+	votes := make(map[uuid.UUID]int)
+	if len(ids) == 0 {
+		return votes, nil
+	}
+
+	rows, err := r.pool.Query(ctx, query, userID, uuidStrings(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get votes: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uuid.UUID
+		var voteType string
+		if err := rows.Scan(&id, &voteType); err != nil {
+			return nil, fmt.Errorf("failed to scan vote: %w", err)
+		}
+		votes[id] = entities.VoteDirection(voteType)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over votes: %w", err)
+	}
+
+	return votes, nil
+}
+
+// uuidStrings converte os IDs para texto, que o pgx envia como uuid[] com o
+// cast explícito na query.
+func uuidStrings(ids []uuid.UUID) []string {
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = id.String()
+	}
+	return values
+}
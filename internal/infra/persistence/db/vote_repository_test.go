@@ -0,0 +1,55 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/elaurentium/exilium-blog-backend/internal/domain/entities"
+)
+
+// setVote soma voteCounts(dir) - voteCounts(previous) aos contadores
+func TestVoteCountDeltas(t *testing.T) {
+	tests := []struct {
+		name          string
+		previous, dir int
+		upvotes       int
+		downvotes     int
+	}{
+		{name: "new upvote", previous: 0, dir: 1, upvotes: 1, downvotes: 0},
+		{name: "new downvote", previous: 0, dir: -1, upvotes: 0, downvotes: 1},
+		{name: "remove upvote", previous: 1, dir: 0, upvotes: -1, downvotes: 0},
+		{name: "remove downvote", previous: -1, dir: 0, upvotes: 0, downvotes: -1},
+		{name: "upvote to downvote", previous: 1, dir: -1, upvotes: -1, downvotes: 1},
+		{name: "downvote to upvote", previous: -1, dir: 1, upvotes: 1, downvotes: -1},
+		{name: "no vote to no vote", previous: 0, dir: 0, upvotes: 0, downvotes: 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			up, down := voteCounts(tc.dir)
+			oldUp, oldDown := voteCounts(tc.previous)
+			if up-oldUp != tc.upvotes || down-oldDown != tc.downvotes {
+				t.Fatalf("delta = (%d, %d), want (%d, %d)", up-oldUp, down-oldDown, tc.upvotes, tc.downvotes)
+			}
+		})
+	}
+}
+
+func TestVoteType(t *testing.T) {
+	tests := []struct {
+		dir      int
+		expected string
+	}{
+		{dir: 1, expected: entities.VoteTypeUp},
+		{dir: -1, expected: entities.VoteTypeDown},
+	}
+
+	for _, tc := range tests {
+		if got := voteType(tc.dir); got != tc.expected {
+			t.Errorf("voteType(%d) = %q, want %q", tc.dir, got, tc.expected)
+		}
+		// O tipo gravado precisa voltar como a mesma direção
+		if got := entities.VoteDirection(voteType(tc.dir)); got != tc.dir {
+			t.Errorf("VoteDirection(voteType(%d)) = %d", tc.dir, got)
+		}
+	}
+}
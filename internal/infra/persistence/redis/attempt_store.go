@@ -0,0 +1,58 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// AttemptStore implementa auth.AttemptStore sobre o Redis, para que o
+// bloqueio valha para todas as instâncias da API.
+type AttemptStore struct {
+	client *redis.Client
+}
+
+func NewAttemptStore(r *RedisClient) *AttemptStore {
+	return &AttemptStore{client: r.client}
+}
+
+func (s *AttemptStore) Incr(ctx context.Context, key string, window time.Duration) (int64, error) {
+	key = "login:failures:" + key
+
+	pipe := s.client.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	// NX: a janela conta a partir da primeira falha
+	pipe.ExpireNX(ctx, key, window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("failed to count login failure for %s: %w", key, err)
+	}
+	return incr.Val(), nil
+}
+
+func (s *AttemptStore) Lock(ctx context.Context, key string, d time.Duration) error {
+	if err := s.client.Set(ctx, "login:lock:"+key, 1, d).Err(); err != nil {
+		return fmt.Errorf("failed to lock %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *AttemptStore) LockedFor(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := s.client.PTTL(ctx, "login:lock:"+key).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check lock for %s: %w", key, err)
+	}
+	// PTTL devolve valores negativos quando a chave não existe
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+func (s *AttemptStore) Reset(ctx context.Context, key string) error {
+	if err := s.client.Del(ctx, "login:failures:"+key, "login:lock:"+key).Err(); err != nil {
+		return fmt.Errorf("failed to reset login failures for %s: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// CooldownStore implementa auth.CooldownStore sobre o Redis: o intervalo é
+// o TTL da chave, criada com SETNX para valer entre instâncias.
+type CooldownStore struct {
+	client *redis.Client
+}
+
+func NewCooldownStore(r *RedisClient) *CooldownStore {
+	return &CooldownStore{client: r.client}
+}
+
+func (s *CooldownStore) Start(ctx context.Context, key string, d time.Duration) (time.Duration, error) {
+	key = "cooldown:" + key
+
+	started, err := s.client.SetNX(ctx, key, 1, d).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to start cooldown %s: %w", key, err)
+	}
+	if started {
+		return 0, nil
+	}
+
+	ttl, err := s.client.PTTL(ctx, key).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check cooldown %s: %w", key, err)
+	}
+	// A chave pode ter expirado entre o SETNX e o PTTL
+	if ttl <= 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
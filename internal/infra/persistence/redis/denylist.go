@@ -0,0 +1,93 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+// TokenDenylist implementa auth.TokenDenylist sobre o Redis, deixando o TTL
+// das chaves cuidar da limpeza.
+type TokenDenylist struct {
+	client *redis.Client
+}
+
+func NewTokenDenylist(r *RedisClient) *TokenDenylist {
+	return &TokenDenylist{client: r.client}
+}
+
+func (d *TokenDenylist) RevokeToken(ctx context.Context, tokenID string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	if err := d.client.Set(ctx, "denylist:token:"+tokenID, 1, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to revoke token %s: %w", tokenID, err)
+	}
+	return nil
+}
+
+func (d *TokenDenylist) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	exists, err := d.client.Exists(ctx, "denylist:token:"+tokenID).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check token %s: %w", tokenID, err)
+	}
+	return exists > 0, nil
+}
+
+// ClaimToken usa SETNX: só a primeira requisição cria a chave.
+func (d *TokenDenylist) ClaimToken(ctx context.Context, tokenID string, ttl time.Duration) (bool, error) {
+	if ttl <= 0 {
+		return false, nil
+	}
+	claimed, err := d.client.SetNX(ctx, "denylist:token:"+tokenID, 1, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim token %s: %w", tokenID, err)
+	}
+	return claimed, nil
+}
+
+func (d *TokenDenylist) RevokeUserTokens(ctx context.Context, userID uuid.UUID, issuedBefore time.Time, ttl time.Duration) error {
+	key := "denylist:user:" + userID.String()
+	if err := d.client.Set(ctx, key, issuedBefore.Unix(), ttl).Err(); err != nil {
+		return fmt.Errorf("failed to revoke tokens for user %s: %w", userID, err)
+	}
+	return nil
+}
+
+func (d *TokenDenylist) UserTokensRevokedBefore(ctx context.Context, userID uuid.UUID) (time.Time, error) {
+	val, err := d.client.Get(ctx, "denylist:user:"+userID.String()).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return time.Time{}, nil
+		}
+		return time.Time{}, fmt.Errorf("failed to check tokens for user %s: %w", userID, err)
+	}
+
+	unix, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid revocation timestamp for user %s: %w", userID, err)
+	}
+	return time.Unix(unix, 0), nil
+}
+
+func (d *TokenDenylist) RevokeSession(ctx context.Context, sessionID uuid.UUID, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	if err := d.client.Set(ctx, "denylist:session:"+sessionID.String(), 1, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to revoke session %s: %w", sessionID, err)
+	}
+	return nil
+}
+
+func (d *TokenDenylist) IsSessionRevoked(ctx context.Context, sessionID uuid.UUID) (bool, error) {
+	exists, err := d.client.Exists(ctx, "denylist:session:"+sessionID.String()).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check session %s: %w", sessionID, err)
+	}
+	return exists > 0, nil
+}
@@ -0,0 +1,13 @@
+-- migrations/005_auth_events.sql
+CREATE TABLE auth_events (
+    id UUID PRIMARY KEY,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    email VARCHAR(100),
+    ip VARCHAR(64),
+    user_agent TEXT,
+    type VARCHAR(50) NOT NULL, -- login_succeeded, login_failed, login_locked, mfa_failed
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_auth_events_user_id ON auth_events(user_id, created_at);
+CREATE INDEX idx_auth_events_created_at ON auth_events(created_at);
@@ -0,0 +1,12 @@
+-- migrations/008_user_identities.sql
+CREATE TABLE user_identities (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(100),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE(provider, subject)
+);
+
+CREATE INDEX idx_user_identities_user_id ON user_identities(user_id);
@@ -0,0 +1,13 @@
+-- migrations/009_audit_logs.sql
+CREATE TABLE audit_logs (
+    id UUID PRIMARY KEY,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    target_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    action VARCHAR(100) NOT NULL, -- impersonation.start, impersonation.request
+    details JSONB NOT NULL DEFAULT '{}',
+    ip VARCHAR(64),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_logs_actor_id ON audit_logs(actor_id, created_at);
+CREATE INDEX idx_audit_logs_target_user_id ON audit_logs(target_user_id, created_at);
@@ -0,0 +1,3 @@
+-- migrations/010_account_deletion.sql
+-- Usado pelo job que remove definitivamente as contas após o período de carência
+CREATE INDEX idx_users_deleted_at ON users(deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,13 @@
+-- migrations/011_follows.sql
+CREATE TABLE follows (
+    follower_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    followee_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (follower_id, followee_id),
+    CONSTRAINT follows_not_self_check CHECK (follower_id <> followee_id)
+);
+
+CREATE INDEX idx_follows_followee_id ON follows(followee_id);
+
+-- Paginação por cursor (created_at, id) do feed de quem o usuário segue
+CREATE INDEX idx_posts_user_id_created_at ON posts(user_id, created_at DESC, id DESC) WHERE deleted_at IS NULL;
@@ -0,0 +1,13 @@
+-- migrations/012_user_blocks.sql
+-- kind: block (esconde o conteúdo e impede respostas) ou mute (só esconde)
+CREATE TABLE user_blocks (
+    blocker_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    blocked_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(10) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (blocker_id, blocked_id, kind),
+    CONSTRAINT user_blocks_kind_check CHECK (kind IN ('block', 'mute')),
+    CONSTRAINT user_blocks_not_self_check CHECK (blocker_id <> blocked_id)
+);
+
+CREATE INDEX idx_user_blocks_blocked_id ON user_blocks(blocked_id);
@@ -0,0 +1,64 @@
+-- migrations/013_karma.sql
+ALTER TABLE users
+    ADD COLUMN post_karma INT NOT NULL DEFAULT 0,
+    ADD COLUMN comment_karma INT NOT NULL DEFAULT 0;
+
+-- Karma mínimo (post + comentário) para publicar no sub
+ALTER TABLE subs ADD COLUMN min_karma INT NOT NULL DEFAULT 0;
+
+-- Soma delta ao karma do autor do post ou comentário votado. Votos no
+-- próprio conteúdo não contam.
+CREATE OR REPLACE FUNCTION apply_vote_karma(voter_id UUID, target_post_id UUID, target_comment_id UUID, delta INT)
+RETURNS VOID AS $$
+BEGIN
+    IF target_post_id IS NOT NULL THEN
+        UPDATE users SET post_karma = post_karma + delta
+        WHERE id = (SELECT user_id FROM posts WHERE id = target_post_id) AND id <> voter_id;
+    ELSE
+        UPDATE users SET comment_karma = comment_karma + delta
+        WHERE id = (SELECT user_id FROM comments WHERE id = target_comment_id) AND id <> voter_id;
+    END IF;
+END;
+$$ language 'plpgsql';
+
+-- Mantém o karma na mesma transação que grava, troca ou remove o voto
+CREATE OR REPLACE FUNCTION update_user_karma()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        PERFORM apply_vote_karma(OLD.user_id, OLD.post_id, OLD.comment_id,
+            CASE WHEN OLD.type = 'upvote' THEN -1 ELSE 1 END);
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        PERFORM apply_vote_karma(NEW.user_id, NEW.post_id, NEW.comment_id,
+            CASE WHEN NEW.type = 'upvote' THEN 1 ELSE -1 END);
+    END IF;
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER update_user_karma_on_vote
+AFTER INSERT OR UPDATE OF type OR DELETE ON votes
+FOR EACH ROW
+EXECUTE FUNCTION update_user_karma();
+
+-- Karma dos votos que já existiam
+UPDATE users u SET post_karma = k.karma
+FROM (
+    SELECT p.user_id, SUM(CASE WHEN v.type = 'upvote' THEN 1 ELSE -1 END) AS karma
+    FROM votes v
+    JOIN posts p ON p.id = v.post_id
+    WHERE v.user_id <> p.user_id
+    GROUP BY p.user_id
+) k
+WHERE u.id = k.user_id;
+
+UPDATE users u SET comment_karma = k.karma
+FROM (
+    SELECT c.user_id, SUM(CASE WHEN v.type = 'upvote' THEN 1 ELSE -1 END) AS karma
+    FROM votes v
+    JOIN comments c ON c.id = v.comment_id
+    WHERE v.user_id <> c.user_id
+    GROUP BY c.user_id
+) k
+WHERE u.id = k.user_id;
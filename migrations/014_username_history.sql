@@ -0,0 +1,12 @@
+-- migrations/014_username_history.sql
+CREATE TABLE username_history (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    old_username VARCHAR(50) NOT NULL,
+    new_username VARCHAR(50) NOT NULL,
+    changed_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_username_history_user_id ON username_history(user_id, changed_at DESC);
+-- Redirecionamento de /users/:oldname e reserva do nome antigo
+CREATE INDEX idx_username_history_old_username ON username_history(LOWER(old_username), changed_at DESC);
@@ -0,0 +1,42 @@
+-- migrations/015_votes.sql
+-- UNIQUE(user_id, post_id, comment_id) nunca barrou votos repetidos, porque
+-- uma das colunas é sempre NULL. Cada alvo passa a ter seu índice parcial.
+ALTER TABLE votes DROP CONSTRAINT IF EXISTS votes_user_id_post_id_comment_id_key;
+
+-- Mantém só o voto mais recente de cada usuário por alvo
+DELETE FROM votes a
+USING votes b
+WHERE a.user_id = b.user_id
+  AND a.post_id = b.post_id
+  AND (a.updated_at, a.id) < (b.updated_at, b.id);
+
+DELETE FROM votes a
+USING votes b
+WHERE a.user_id = b.user_id
+  AND a.comment_id = b.comment_id
+  AND (a.updated_at, a.id) < (b.updated_at, b.id);
+
+CREATE UNIQUE INDEX votes_user_post_key ON votes(user_id, post_id) WHERE post_id IS NOT NULL;
+CREATE UNIQUE INDEX votes_user_comment_key ON votes(user_id, comment_id) WHERE comment_id IS NOT NULL;
+
+-- Votos alteram os contadores, mas não são uma edição do post ou comentário
+DROP TRIGGER update_posts_modtime ON posts;
+CREATE TRIGGER update_posts_modtime
+BEFORE UPDATE OF title, content, is_locked, is_pinned ON posts
+FOR EACH ROW
+EXECUTE FUNCTION update_modified_column();
+
+DROP TRIGGER update_comments_modtime ON comments;
+CREATE TRIGGER update_comments_modtime
+BEFORE UPDATE OF content ON comments
+FOR EACH ROW
+EXECUTE FUNCTION update_modified_column();
+
+-- Recalcula os contadores a partir dos votos
+UPDATE posts p SET
+    upvotes = (SELECT COUNT(*) FROM votes v WHERE v.post_id = p.id AND v.type = 'upvote'),
+    downvotes = (SELECT COUNT(*) FROM votes v WHERE v.post_id = p.id AND v.type = 'downvote');
+
+UPDATE comments c SET
+    upvotes = (SELECT COUNT(*) FROM votes v WHERE v.comment_id = c.id AND v.type = 'upvote'),
+    downvotes = (SELECT COUNT(*) FROM votes v WHERE v.comment_id = c.id AND v.type = 'downvote');
@@ -0,0 +1,33 @@
+-- migrations/016_post_ranking.sql
+-- Pontuações de ordenação calculadas pelo próprio Postgres sempre que os
+-- contadores mudam, para as listagens usarem índices em vez de ordenar
+-- por expressão.
+ALTER TABLE posts
+    ADD COLUMN score INT GENERATED ALWAYS AS (upvotes - downvotes) STORED,
+    -- "hot": log10 do saldo de votos mais a idade do post; 45000s (12,5h)
+    -- valem tanto quanto 10x mais votos
+    ADD COLUMN hot_score DOUBLE PRECISION GENERATED ALWAYS AS (
+        SIGN(upvotes - downvotes) * LOG(GREATEST(ABS(upvotes - downvotes), 1)::DOUBLE PRECISION)
+        + (EXTRACT(EPOCH FROM created_at)::DOUBLE PRECISION - 1134028003) / 45000
+    ) STORED,
+    -- "controversial": muitos votos, divididos quase meio a meio
+    ADD COLUMN controversy_score DOUBLE PRECISION GENERATED ALWAYS AS (
+        CASE
+            WHEN upvotes <= 0 OR downvotes <= 0 THEN 0
+            ELSE POWER(upvotes + downvotes,
+                CASE WHEN upvotes > downvotes
+                    THEN downvotes::DOUBLE PRECISION / upvotes
+                    ELSE upvotes::DOUBLE PRECISION / downvotes
+                END)
+        END
+    ) STORED;
+
+CREATE INDEX idx_posts_hot ON posts(hot_score DESC) WHERE deleted_at IS NULL;
+CREATE INDEX idx_posts_score ON posts(score DESC, created_at DESC) WHERE deleted_at IS NULL;
+CREATE INDEX idx_posts_controversy ON posts(controversy_score DESC) WHERE deleted_at IS NULL;
+CREATE INDEX idx_posts_created_at ON posts(created_at DESC) WHERE deleted_at IS NULL;
+
+CREATE INDEX idx_posts_sub_hot ON posts(sub_id, hot_score DESC) WHERE deleted_at IS NULL;
+CREATE INDEX idx_posts_sub_score ON posts(sub_id, score DESC, created_at DESC) WHERE deleted_at IS NULL;
+CREATE INDEX idx_posts_sub_controversy ON posts(sub_id, controversy_score DESC) WHERE deleted_at IS NULL;
+CREATE INDEX idx_posts_sub_created_at ON posts(sub_id, created_at DESC) WHERE deleted_at IS NULL;
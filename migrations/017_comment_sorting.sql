@@ -0,0 +1,37 @@
+-- migrations/017_comment_sorting.sql
+-- Pontuações de ordenação dos comentários, geradas a partir dos contadores
+ALTER TABLE comments
+    ADD COLUMN score INT GENERATED ALWAYS AS (upvotes - downvotes) STORED,
+    -- "best": limite inferior do intervalo de Wilson (confiança de 80%) para a
+    -- proporção de upvotes; poucos votos não bastam para subir
+    ADD COLUMN wilson_score DOUBLE PRECISION GENERATED ALWAYS AS (
+        CASE
+            WHEN upvotes + downvotes = 0 THEN 0
+            ELSE (
+                upvotes::DOUBLE PRECISION / (upvotes + downvotes)
+                + 1.642374 / (2 * (upvotes + downvotes))
+                - 1.281552 * SQRT(
+                    upvotes::DOUBLE PRECISION * downvotes / (upvotes + downvotes) + 1.642374 / 4
+                ) / (upvotes + downvotes)
+            ) / (1 + 1.642374 / (upvotes + downvotes))
+        END
+    ) STORED,
+    ADD COLUMN controversy_score DOUBLE PRECISION GENERATED ALWAYS AS (
+        CASE
+            WHEN upvotes <= 0 OR downvotes <= 0 THEN 0
+            ELSE POWER(upvotes + downvotes,
+                CASE WHEN upvotes > downvotes
+                    THEN downvotes::DOUBLE PRECISION / upvotes
+                    ELSE upvotes::DOUBLE PRECISION / downvotes
+                END)
+        END
+    ) STORED;
+
+CREATE INDEX idx_comments_post_wilson ON comments(post_id, wilson_score DESC) WHERE deleted_at IS NULL;
+CREATE INDEX idx_comments_post_score ON comments(post_id, score DESC) WHERE deleted_at IS NULL;
+CREATE INDEX idx_comments_post_created_at ON comments(post_id, created_at) WHERE deleted_at IS NULL;
+CREATE INDEX idx_comments_parent_id ON comments(parent_id) WHERE deleted_at IS NULL;
+
+-- Ordenação padrão dos comentários escolhida pelo autor ou pelos moderadores
+ALTER TABLE posts ADD COLUMN suggested_sort VARCHAR(20)
+    CHECK (suggested_sort IN ('best', 'top', 'new', 'old', 'controversial', 'qa'));
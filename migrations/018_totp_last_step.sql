@@ -0,0 +1,3 @@
+-- migrations/018_totp_last_step.sql
+-- Último passo TOTP aceito: cada código do app vale uma única vez
+ALTER TABLE users ADD COLUMN totp_last_step BIGINT;
@@ -0,0 +1,4 @@
+-- migrations/019_username_lower_unique.sql
+-- Nomes de usuário são únicos sem diferenciar maiúsculas: a checagem do
+-- serviço não basta contra dois cadastros simultâneos
+CREATE UNIQUE INDEX idx_users_username_lower ON users (LOWER(username));
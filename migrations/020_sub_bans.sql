@@ -0,0 +1,12 @@
+-- migrations/020_sub_bans.sql
+-- Usuários banidos de um sub não podem postar nem comentar nele
+CREATE TABLE sub_bans (
+    sub_id UUID NOT NULL REFERENCES subs(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    banned_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (sub_id, user_id)
+);
+
+CREATE INDEX idx_sub_bans_user_id ON sub_bans(user_id);